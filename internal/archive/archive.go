@@ -0,0 +1,140 @@
+// Package archive keeps the results of finished games, in memory and
+// optionally as one JSON file per game in a directory.
+package archive
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/record"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of one finished game.
+type Result struct {
+	GameID   string              `json:"game_id"`
+	Finished time.Time           `json:"finished"`
+	Rounds   int                 `json:"rounds"`
+	Seed     uint64              `json:"seed"`
+	Scores   []engine.ScoreEntry `json:"scores"`  // ranked, best first
+	Winners  []string            `json:"winners"` // player IDs
+	Stats    *engine.Stats       `json:"stats,omitempty"`
+	Rated    bool                `json:"rated,omitempty"`    // marked rated in the lobby, so no hints were given
+	Imported bool                `json:"imported,omitempty"` // uploaded as a record rather than played here
+
+	Cities map[string][]engine.District `json:"cities"` // final city of each player
+
+	Record *record.Record `json:"record,omitempty"` // the whole game, for export and replay
+}
+
+// Archive stores results by game ID. It is safe for concurrent use.
+type Archive struct {
+	mu      sync.Mutex
+	dir     string // "" keeps results in memory only
+	results map[string]Result
+}
+
+// New opens an archive. If dir is not empty, results already saved there
+// are loaded and new ones are written to it.
+func New(dir string) (*Archive, error) {
+	a := &Archive{dir: dir, results: make(map[string]Result)}
+	if dir == "" {
+		return a, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("archive: %w", err)
+		}
+		var r Result
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("archive: %s: %w", f, err)
+		}
+		a.results[r.GameID] = r
+	}
+	return a, nil
+}
+
+// FromGame builds the result of a finished game.
+func FromGame(gameID string, g *engine.Game) Result {
+	r := Result{
+		GameID:   gameID,
+		Finished: time.Now().UTC(),
+		Rounds:   g.Round,
+		Seed:     g.Seed,
+		Scores:   g.Scores,
+		Winners:  engine.Winners(g.Scores),
+		Stats:    g.Stats(),
+		Cities:   make(map[string][]engine.District),
+	}
+	rec := record.FromGame(gameID, g)
+	r.Record = &rec
+	for _, p := range g.Players {
+		r.Cities[p.ID] = p.City
+	}
+	return r
+}
+
+// Save stores a result, replacing any earlier one for the same game. The
+// game ID names the file, so it must pass record.ValidGameID.
+func (a *Archive) Save(r Result) error {
+	if !record.ValidGameID(r.GameID) {
+		return fmt.Errorf("archive: %w: %q", record.ErrGameID, r.GameID)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.results[r.GameID] = r
+	if a.dir == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(a.dir, r.GameID+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	return nil
+}
+
+// Get returns the result of a game.
+func (a *Archive) Get(gameID string) (Result, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r, ok := a.results[gameID]
+	return r, ok
+}
+
+// List returns all results, most recently finished first.
+func (a *Archive) List() []Result {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]Result, 0, len(a.results))
+	for _, r := range a.results {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Finished.After(out[j].Finished)
+	})
+	return out
+}
+
+// Played returns the results of games played on this server, most recently
+// finished first. Imported results are left out: anyone can upload a record,
+// so they don't count towards anything added up from the archive.
+func (a *Archive) Played() []Result {
+	out := a.List()
+	return slices.DeleteFunc(out, func(r Result) bool { return r.Imported })
+}
@@ -0,0 +1,55 @@
+package archive_test
+
+import (
+	"citadels/internal/archive"
+	"citadels/internal/engine"
+	"testing"
+	"time"
+)
+
+func TestArchivePersists(t *testing.T) {
+	dir := t.TempDir()
+	a, err := archive.New(dir)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	old := archive.Result{GameID: "old", Finished: time.Unix(100, 0)}
+	res := archive.Result{
+		GameID:   "g1",
+		Finished: time.Unix(200, 0),
+		Scores:   []engine.ScoreEntry{{PlayerID: "A", Total: 20, Rank: 1, Winner: true}},
+		Winners:  []string{"A"},
+	}
+	for _, r := range []archive.Result{old, res} {
+		if err := a.Save(r); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	// A second archive on the same directory sees both results
+	b, err := archive.New(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, ok := b.Get("g1")
+	if !ok || len(got.Winners) != 1 || got.Winners[0] != "A" || !got.Scores[0].Winner {
+		t.Errorf("reloaded result: %+v", got)
+	}
+	if list := b.List(); len(list) != 2 || list[0].GameID != "g1" {
+		t.Errorf("list should be newest first: %+v", list)
+	}
+
+	// Imported results are kept but not counted; IDs can't leave the directory
+	if err := b.Save(archive.Result{GameID: "import-1", Finished: time.Unix(300, 0), Imported: true}); err != nil {
+		t.Fatalf("save imported: %v", err)
+	}
+	if played := b.Played(); len(played) != 2 || played[0].GameID != "g1" {
+		t.Errorf("played should leave out imported results: %+v", played)
+	}
+	if _, ok := b.Get("import-1"); !ok {
+		t.Error("imported result not found by ID")
+	}
+	if err := b.Save(archive.Result{GameID: "../escape"}); err == nil {
+		t.Error("saved a result under a path")
+	}
+}
@@ -0,0 +1,322 @@
+package bot
+
+import (
+	"citadels/internal/engine"
+	"slices"
+	"strconv"
+)
+
+// Advice is a legal action with how much the normal bot likes it and why.
+type Advice struct {
+	Action engine.Action     `json:"action"`
+	Score  float64           `json:"score"`            // higher is better; only comparable within one Advise call
+	Reason string            `json:"reason"`           // a short key such as "build_best"
+	Params map[string]string `json:"params,omitempty"` // names and numbers the reason refers to
+}
+
+// Advise ranks a player's legal actions by the normal bot's rules of thumb,
+// best first. A Magician discard is narrowed to the cards worth throwing
+// away. It sees only the player's own view, so it reveals nothing.
+func Advise(view engine.PlayerViewData, playerID string, legal []engine.Action) []Advice {
+	me := self(view, playerID)
+	acts := byType(legal)
+	role := engine.ParseRole(view.CurrentRole)
+
+	var out []Advice
+	out = append(out, draftValues(view, me, playerID, acts[engine.ActionDraftPick])...)
+	out = append(out, keepValues(view, me, acts[engine.ActionKeepCard])...)
+	out = append(out, buildValues(view, me, acts[engine.ActionBuild])...)
+	for _, a := range acts[engine.ActionCollectGold] {
+		out = append(out, Advice{Action: a, Score: 7, Reason: "collect_gold",
+			Params: map[string]string{"gold": strconv.Itoa(view.CollectGoldAmount)}})
+	}
+	out = append(out, turnActionValues(view, me, acts)...)
+
+	switch role {
+	case engine.RoleAssassin:
+		out = append(out, targetValues(acts[engine.ActionAbility], murderOrder, view.Characters, "assassin_target")...)
+	case engine.RoleThief:
+		out = append(out, targetValues(acts[engine.ActionAbility], robOrder, view.Characters, "thief_target")...)
+	case engine.RoleMagician:
+		out = append(out, magicianValues(view, me, acts[engine.ActionAbility])...)
+	case engine.RoleWarlord:
+		out = append(out, warlordValues(view, acts[engine.ActionAbility])...)
+	}
+
+	for _, a := range acts[engine.ActionLabDiscard] {
+		d := handCard(view, a.CardID)
+		v := Advice{Action: a, Score: 0.3, Reason: "lab", Params: map[string]string{"district": d.Name}}
+		if inCity(me.City, d) {
+			v.Score, v.Reason = 1.2, "lab_duplicate"
+		}
+		out = append(out, v)
+	}
+	for _, a := range acts[engine.ActionSmithyDraw] {
+		v := Advice{Action: a, Score: 0.4, Reason: "smithy"}
+		if me.Gold >= 4 && len(view.Hand) <= 1 {
+			v.Score, v.Reason = 1.3, "smithy_hand_thin"
+		}
+		out = append(out, v)
+	}
+	for _, a := range acts[engine.ActionGraveyardRespond] {
+		v := Advice{Action: a, Score: 1, Reason: "graveyard_decline"}
+		keep := view.Decision != nil && view.Decision.Card != nil && !inCity(me.City, *view.Decision.Card) && me.Gold >= 2
+		if keep == (a.ExtraData == "accept") {
+			v.Score = 2
+		}
+		if a.ExtraData == "accept" {
+			v.Reason = "graveyard_keep"
+			if view.Decision != nil && view.Decision.Card != nil {
+				v.Params = map[string]string{"district": view.Decision.Card.Name}
+			}
+		}
+		out = append(out, v)
+	}
+	for _, a := range acts[engine.ActionEndTurn] {
+		out = append(out, Advice{Action: a, Score: 1, Reason: "end_turn"})
+	}
+
+	slices.SortStableFunc(out, func(a, b Advice) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	return out
+}
+
+// draftValues scores each character for the player's situation.
+func draftValues(view engine.PlayerViewData, me engine.PublicPlayerData, id string, as []engine.Action) []Advice {
+	colors := map[engine.DistrictColor]int{}
+	for _, d := range me.City {
+		colors[d.Color]++
+	}
+	affordable := 0
+	for _, d := range view.Hand {
+		if d.Cost <= me.Gold && !inCity(me.City, d) {
+			affordable++
+		}
+	}
+	leader := leadingCity(view, id)
+	count := func(c engine.DistrictColor) map[string]string {
+		return map[string]string{"count": strconv.Itoa(colors[c])}
+	}
+
+	var out []Advice
+	for _, a := range as {
+		v := Advice{Action: a}
+		switch a.Character {
+		case engine.RoleAssassin:
+			v.Score, v.Reason = 2, "draft_assassin"
+		case engine.RoleThief:
+			v.Score, v.Reason = 1, "draft_thief"
+			if me.Gold <= 2 {
+				v.Score, v.Reason = 3, "draft_thief_poor"
+			}
+		case engine.RoleMagician:
+			v.Reason = "draft_magician"
+			if len(view.Hand) == 0 {
+				v.Score, v.Reason = 4, "draft_magician_empty"
+			} else if affordable == 0 {
+				v.Score, v.Reason = 2, "draft_magician_stuck"
+			}
+		case engine.RoleKing:
+			v.Score, v.Reason, v.Params = 1.5+float64(colors[engine.ColorNoble]), "draft_king", count(engine.ColorNoble)
+		case engine.RoleBishop:
+			v.Score, v.Reason, v.Params = float64(colors[engine.ColorReligious]), "draft_bishop", count(engine.ColorReligious)
+			if len(me.City) >= 5 {
+				v.Score, v.Reason = v.Score+2, "draft_bishop_protect"
+			}
+		case engine.RoleMerchant:
+			v.Score, v.Reason, v.Params = 1.5+float64(colors[engine.ColorTrade]), "draft_merchant", count(engine.ColorTrade)
+		case engine.RoleArchitect:
+			v.Reason = "draft_architect"
+			if me.Gold >= 4 && len(view.Hand) >= 2 {
+				v.Score, v.Reason = 3.5, "draft_architect_build"
+			} else if len(view.Hand) <= 1 {
+				v.Score, v.Reason = 1.5, "draft_architect_draw"
+			}
+		case engine.RoleWarlord:
+			v.Score, v.Reason, v.Params = float64(colors[engine.ColorMilitary]), "draft_warlord", count(engine.ColorMilitary)
+			if leader >= 5 {
+				v.Score, v.Reason = v.Score+2, "draft_warlord_leader"
+				v.Params = map[string]string{"count": strconv.Itoa(leader)}
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// keepValues prefers a new district the player can afford soon, most
+// expensive first.
+func keepValues(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) []Advice {
+	var out []Advice
+	for _, a := range as {
+		if a.Index >= len(view.DrawnCards) {
+			continue
+		}
+		d := view.DrawnCards[a.Index]
+		score, reason := d.Cost, "keep"
+		if d.Cost > me.Gold+4 {
+			score, reason = score-5, "keep_expensive"
+		}
+		if inCity(me.City, d) || slices.ContainsFunc(view.Hand, func(h engine.District) bool { return h.Name == d.Name }) {
+			score, reason = score-10, "keep_duplicate"
+		}
+		out = append(out, Advice{Action: a, Score: 5 + float64(score)/10, Reason: reason,
+			Params: map[string]string{"district": d.Name, "cost": strconv.Itoa(d.Cost)}})
+	}
+	return out
+}
+
+// buildValues prefers the most expensive card, then colors the city lacks.
+func buildValues(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) []Advice {
+	var out []Advice
+	best := -1
+	for _, a := range as {
+		d := handCard(view, a.CardID)
+		score, reason := d.Cost*2, "build"
+		if !slices.ContainsFunc(me.City, func(c engine.District) bool { return c.Color == d.Color }) {
+			score, reason = score+1, "build_new_color"
+		}
+		if best < 0 || float64(score) > out[best].Score {
+			best = len(out)
+		}
+		out = append(out, Advice{Action: a, Score: float64(score), Reason: reason,
+			Params: map[string]string{"district": d.Name, "cost": strconv.Itoa(d.Cost), "color": d.Color.String()}})
+	}
+	if best >= 0 {
+		out[best].Reason = "build_best"
+	}
+	for i := range out {
+		out[i].Score = 2 + out[i].Score/10
+	}
+	return out
+}
+
+// turnActionValues draws when the hand has nothing worth saving for,
+// otherwise takes gold.
+func turnActionValues(view engine.PlayerViewData, me engine.PublicPlayerData, acts map[engine.ActionType][]engine.Action) []Advice {
+	var wanted []engine.District
+	for _, d := range view.Hand {
+		if !inCity(me.City, d) {
+			wanted = append(wanted, d)
+		}
+	}
+	draw := Advice{Score: 3, Reason: "draw"}
+	gold := Advice{Score: 4, Reason: "gold"}
+	switch {
+	case len(wanted) == 0:
+		draw.Score, draw.Reason = 4.5, "draw_nothing_to_build"
+	case len(wanted) == 1 && me.Gold >= 4:
+		draw.Score, draw.Reason = 4.5, "draw_hand_thin"
+	default:
+		target := wanted[0]
+		for _, d := range wanted {
+			if d.Cost > target.Cost {
+				target = d
+			}
+		}
+		gold.Reason, gold.Params = "gold_save", map[string]string{"district": target.Name}
+	}
+
+	var out []Advice
+	for _, a := range acts[engine.ActionDrawCards] {
+		draw.Action = a
+		out = append(out, draw)
+	}
+	for _, a := range acts[engine.ActionTakeGold] {
+		gold.Action = a
+		out = append(out, gold)
+	}
+	return out
+}
+
+// targetValues ranks the Assassin's or Thief's targets by order, leaving
+// the player's own characters last.
+func targetValues(as []engine.Action, order []engine.CharacterRole, mine []string, reason string) []Advice {
+	var out []Advice
+	for _, a := range as {
+		v := Advice{Action: a, Reason: reason, Params: map[string]string{"character": a.Character.String()}}
+		if i := slices.Index(order, a.Character); i >= 0 {
+			v.Score = 6 - float64(i)/2
+		}
+		if slices.Contains(mine, a.Character.String()) {
+			v.Score, v.Reason = 0, "own_character"
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// magicianValues prefers swapping for a bigger hand, then redrawing cards
+// the player can't use.
+func magicianValues(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) []Advice {
+	var out []Advice
+	for _, a := range as {
+		switch a.ExtraData {
+		case "swap_hand":
+			for _, p := range view.Players {
+				if p.ID != a.Target {
+					continue
+				}
+				v := Advice{Action: a, Score: 0.5, Reason: "magician_swap_small",
+					Params: map[string]string{"player": p.Name, "cards": strconv.Itoa(p.HandSize)}}
+				if p.HandSize > len(view.Hand)+1 {
+					v.Score, v.Reason = 6+float64(p.HandSize)/10, "magician_swap"
+				}
+				out = append(out, v)
+			}
+		case "discard_draw":
+			var useless []int
+			for _, i := range a.Indices {
+				if d := view.Hand[i]; inCity(me.City, d) || d.Cost > me.Gold+5 {
+					useless = append(useless, i)
+				}
+			}
+			v := Advice{Action: a, Score: 0.2, Reason: "magician_discard_none"}
+			if len(useless) > 0 {
+				v.Action.Indices, v.Action.AnySubset = useless, false
+				v.Score, v.Reason = 5, "magician_discard"
+				v.Params = map[string]string{"cards": strconv.Itoa(len(useless))}
+			}
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// warlordValues favours destroying in the biggest city when it's close to
+// ending the game, or anywhere when it's free.
+func warlordValues(view engine.PlayerViewData, as []engine.Action) []Advice {
+	endSize := engine.DefaultConfig().EndCitySize
+	var out []Advice
+	for _, a := range as {
+		var target engine.PublicPlayerData
+		cost := 0
+		for _, p := range view.Players {
+			if p.ID == a.Target {
+				target = p
+				if i := engine.IndexOfCard(p.City, a.CardID, a.DistrictName); i >= 0 {
+					cost = engine.DestroyCost(p.City, p.City[i])
+				}
+			}
+		}
+		v := Advice{Action: a, Score: 0.5, Reason: "warlord_costly", Params: map[string]string{
+			"player": target.Name, "district": a.DistrictName, "cost": strconv.Itoa(cost), "count": strconv.Itoa(len(target.City)),
+		}}
+		switch {
+		case len(target.City) >= endSize-2:
+			v.Score, v.Reason = 1.5, "warlord_threat"
+		case cost <= 0:
+			v.Score, v.Reason = 1.5, "warlord_free"
+		}
+		v.Score += float64(len(target.City)*3-cost) / 100
+		out = append(out, v)
+	}
+	return out
+}
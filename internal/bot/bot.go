@@ -0,0 +1,98 @@
+// Package bot implements computer players that fill empty seats. A Strategy
+// sees what a human in the same seat would see — the player's own view —
+// together with the legal actions, and picks one of them.
+package bot
+
+import (
+	"citadels/internal/engine"
+	"errors"
+	"math/rand/v2"
+)
+
+// Difficulty selects a strategy.
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"   // mostly random, never wastes a turn
+	Normal Difficulty = "normal" // simple heuristics for every decision
+	Hard   Difficulty = "hard"   // tree search over sampled hidden information, see ISMCTS
+)
+
+// Difficulties lists the difficulties New accepts.
+var Difficulties = []Difficulty{Easy, Normal, Hard}
+
+var ErrDifficulty = errors.New("unknown bot difficulty")
+
+// Strategy decides a bot's next action. Act is only called when legal is not
+// empty, and must return one of the legal actions; an entry with AnySubset
+// may be narrowed to a subset of its indices.
+type Strategy interface {
+	Act(view engine.PlayerViewData, legal []engine.Action) engine.Action
+}
+
+// Searcher is a Strategy that plans on a copy of the game. The copy must come
+// from engine.Game.Determinize for the bot's player, so it holds nothing the
+// player couldn't see. Search takes a while and only reads the copy, so
+// callers can run it off the game's goroutine.
+type Searcher interface {
+	Strategy
+	Search(g *engine.Game) engine.Action
+}
+
+// New returns a strategy for the player with the given ID. The seed makes a
+// bot's choices repeatable.
+func New(d Difficulty, playerID string, seed uint64) (Strategy, error) {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	switch d {
+	case Easy:
+		return &easy{id: playerID, rng: rng}, nil
+	case Normal:
+		return &normal{id: playerID, rng: rng}, nil
+	case Hard:
+		return NewISMCTS(playerID, seed), nil
+	}
+	return nil, ErrDifficulty
+}
+
+// Names are given to bots in the lobby, in order, skipping names in use.
+var Names = []string{"Aldric", "Beatrix", "Cedric", "Dorothea", "Edmund", "Fiona", "Godfrey"}
+
+// byType groups the legal actions by type.
+func byType(legal []engine.Action) map[engine.ActionType][]engine.Action {
+	out := make(map[engine.ActionType][]engine.Action)
+	for _, a := range legal {
+		out[a.Type] = append(out[a.Type], a)
+	}
+	return out
+}
+
+// self returns the bot's own public data from the view.
+func self(view engine.PlayerViewData, id string) engine.PublicPlayerData {
+	for _, p := range view.Players {
+		if p.ID == id {
+			return p
+		}
+	}
+	return engine.PublicPlayerData{}
+}
+
+// inCity reports whether a district with the card's name is already built,
+// which would make the card unbuildable (apart from the Haunted City).
+func inCity(city []engine.District, card engine.District) bool {
+	for _, d := range city {
+		if d.Name == card.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// handCard returns the hand card with the given ID.
+func handCard(view engine.PlayerViewData, id int) engine.District {
+	for _, d := range view.Hand {
+		if d.ID == id {
+			return d
+		}
+	}
+	return engine.District{}
+}
@@ -0,0 +1,168 @@
+package bot_test
+
+import (
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"citadels/internal/engine/abilities"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// iterations bounds hard bots' searches, so tests don't depend on timing.
+var iterations = 200
+
+// play runs a game between bots of the given difficulties and returns the
+// finished game.
+func play(t *testing.T, seed uint64, seats ...bot.Difficulty) *engine.Game {
+	t.Helper()
+	var players []*engine.Player
+	bots := map[string]bot.Strategy{}
+	for i, d := range seats {
+		id := fmt.Sprintf("p%d", i)
+		players = append(players, engine.NewPlayer(id, string(d)))
+		s, err := bot.New(d, id, seed+uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h, ok := s.(*bot.ISMCTS); ok {
+			h.Budget, h.MaxIterations = 0, iterations
+		}
+		bots[id] = s
+	}
+	cfg := engine.DefaultConfig()
+	cfg.Seed = seed
+	g := engine.NewGame(players, cfg, abilities.NewRegistry())
+	g.StartGame()
+	for step := 0; g.Phase != engine.PhaseGameOver; step++ {
+		if step > 5000 {
+			t.Fatalf("seed %d: game did not finish", seed)
+		}
+		moved := false
+		for _, p := range g.Players {
+			legal := g.LegalActions(p.ID)
+			if len(legal) == 0 {
+				continue
+			}
+			var a engine.Action
+			if s, ok := bots[p.ID].(bot.Searcher); ok {
+				a = s.Search(g.Determinize(p.ID, uint64(step)))
+			} else {
+				a = bots[p.ID].Act(g.ViewFor(p.ID), legal)
+			}
+			if _, err := g.Apply(p.ID, a); err != nil {
+				t.Fatalf("seed %d: %s (%s) chose %+v: %v", seed, p.ID, p.Name, a, err)
+			}
+			moved = true
+			break
+		}
+		if !moved {
+			t.Fatalf("seed %d: nobody can act in %s", seed, g.Phase)
+		}
+	}
+	return g
+}
+
+func TestBotsFinishGames(t *testing.T) {
+	wins := map[string]int{}
+	for seed := uint64(1); seed <= 40; seed++ {
+		seats := []bot.Difficulty{bot.Easy, bot.Normal, bot.Easy, bot.Normal}[:2+seed%3]
+		g := play(t, seed, seats...)
+		for _, id := range engine.Winners(g.Scores) {
+			wins[g.GetPlayer(id).Name]++
+		}
+	}
+	if wins[string(bot.Normal)] <= wins[string(bot.Easy)]*2 {
+		t.Errorf("normal bots should clearly beat easy ones, wins: %v", wins)
+	}
+}
+
+func TestHardBot(t *testing.T) {
+	iterations = 50
+	defer func() { iterations = 200 }()
+	play(t, 3, bot.Hard, bot.Normal, bot.Easy)
+
+	// Without an iteration cap, the budget ends the search
+	g := engine.NewGame([]*engine.Player{engine.NewPlayer("a", "A"), engine.NewPlayer("b", "B")},
+		engine.DefaultConfig(), abilities.NewRegistry())
+	g.StartGame()
+	picker := g.Draft.CurrentPickerID()
+	h := bot.NewISMCTS(picker, 1)
+	h.Budget = 50 * time.Millisecond
+	start := time.Now()
+	if a := h.Search(g.Determinize(picker, 1)); !g.IsLegal(picker, a) {
+		t.Errorf("search chose an illegal %+v", a)
+	}
+	if took := time.Since(start); took > 500*time.Millisecond {
+		t.Errorf("search took %v with a %v budget", took, h.Budget)
+	}
+}
+
+func TestNewDifficulty(t *testing.T) {
+	if _, err := bot.New("grandmaster", "p0", 1); !errors.Is(err, bot.ErrDifficulty) {
+		t.Errorf("unknown difficulty: got %v", err)
+	}
+}
+
+func TestAdvise(t *testing.T) {
+	players := []*engine.Player{engine.NewPlayer("p0", "A"), engine.NewPlayer("p1", "B"), engine.NewPlayer("p2", "C")}
+	cfg := engine.DefaultConfig()
+	cfg.Seed = 7
+	g := engine.NewGame(players, cfg, abilities.NewRegistry())
+	g.StartGame()
+	bots := map[string]bot.Strategy{}
+	for i, p := range players {
+		bots[p.ID], _ = bot.New(bot.Normal, p.ID, uint64(i))
+	}
+	for step := 0; g.Phase != engine.PhaseGameOver && step < 5000; step++ {
+		for _, p := range g.Players {
+			legal := g.LegalActions(p.ID)
+			if len(legal) == 0 {
+				continue
+			}
+			advice := bot.Advise(g.ViewFor(p.ID), p.ID, legal)
+			if len(advice) != len(legal) {
+				t.Fatalf("step %d: %d pieces of advice for %d legal actions", step, len(advice), len(legal))
+			}
+			best := 0
+			for i, a := range advice {
+				if i > 0 && a.Score > advice[i-1].Score {
+					t.Fatalf("step %d: advice not ranked: %+v", step, advice)
+				}
+				if a.Reason == "" || !g.IsLegal(p.ID, a.Action) {
+					t.Fatalf("step %d: bad advice %+v", step, a)
+				}
+				if a.Reason == "build_best" {
+					best++
+				}
+			}
+			if best > 1 {
+				t.Fatalf("step %d: %d best builds", step, best)
+			}
+			if _, err := g.Apply(p.ID, bots[p.ID].Act(g.ViewFor(p.ID), legal)); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	if g.Phase != engine.PhaseGameOver {
+		t.Fatal("game did not finish")
+	}
+}
+
+func TestAdviseWarlordGreatWall(t *testing.T) {
+	temple := engine.District{ID: 2, Name: "Temple", Color: engine.ColorReligious, Cost: 1}
+	var view engine.PlayerViewData
+	view.CurrentRole = engine.RoleWarlord.String()
+	view.Players = []engine.PublicPlayerData{
+		{ID: "p0", Name: "A", Gold: 3},
+		{ID: "p1", Name: "B", City: []engine.District{{ID: 1, Name: "Great Wall", Color: engine.ColorSpecial, Cost: 6}, temple}},
+	}
+	legal := []engine.Action{{Type: engine.ActionAbility, Target: "p1", CardID: temple.ID, DistrictName: temple.Name}}
+
+	a := bot.Advise(view, "p0", legal)[0]
+	if a.Reason == "warlord_free" || a.Params["cost"] != "1" {
+		t.Errorf("the Great Wall makes the Temple cost 1: %+v", a)
+	}
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"citadels/internal/engine"
+	"math/rand/v2"
+)
+
+// easy plays at random, except that it always takes a turn action, builds
+// whatever it can afford and never pays for a destroyed district.
+type easy struct {
+	id  string
+	rng *rand.Rand
+}
+
+func (b *easy) Act(view engine.PlayerViewData, legal []engine.Action) engine.Action {
+	acts := byType(legal)
+	pick := func(as []engine.Action) engine.Action { return as[b.rng.IntN(len(as))] }
+
+	switch {
+	case len(acts[engine.ActionGraveyardRespond]) > 0:
+		for _, a := range acts[engine.ActionGraveyardRespond] {
+			if a.ExtraData == "decline" {
+				return a
+			}
+		}
+		return acts[engine.ActionGraveyardRespond][0]
+	case len(acts[engine.ActionDraftPick]) > 0:
+		return pick(acts[engine.ActionDraftPick])
+	case len(acts[engine.ActionKeepCard]) > 0:
+		return pick(acts[engine.ActionKeepCard])
+	case len(acts[engine.ActionCollectGold]) > 0:
+		return acts[engine.ActionCollectGold][0]
+	}
+
+	if turn := append(acts[engine.ActionTakeGold], acts[engine.ActionDrawCards]...); len(turn) > 0 {
+		return pick(turn)
+	}
+	if builds := acts[engine.ActionBuild]; len(builds) > 0 {
+		return pick(builds)
+	}
+	if abilities := acts[engine.ActionAbility]; len(abilities) > 0 && b.rng.IntN(2) == 0 {
+		return pick(abilities)
+	}
+	if end := acts[engine.ActionEndTurn]; len(end) > 0 {
+		return end[0]
+	}
+	return legal[0]
+}
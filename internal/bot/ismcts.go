@@ -0,0 +1,286 @@
+package bot
+
+import (
+	"citadels/internal/engine"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultBudget is the search time a hard bot spends on each decision.
+const DefaultBudget = time.Second
+
+// ISMCTS is the hard bot: single-observer information-set Monte Carlo tree
+// search. Every iteration deals the hidden information anew with
+// engine.Game.Determinize, walks one shared tree of actions by UCB, then
+// plays a quick rollout on the determinized copy. Nodes are matched by what
+// an action means — build "Castle" rather than card #42 — so branches for
+// opponents' unseen cards are shared between determinizations.
+type ISMCTS struct {
+	Budget        time.Duration // search time per decision
+	MaxIterations int           // stop after this many iterations; 0 for no limit
+	Exploration   float64       // UCB exploration constant
+	RolloutRounds int           // rounds a rollout plays past the current one before scoring
+
+	id       string
+	rng      *rand.Rand
+	fallback normal
+}
+
+// NewISMCTS returns a hard bot for the player with the given ID.
+func NewISMCTS(playerID string, seed uint64) *ISMCTS {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	return &ISMCTS{
+		Budget:        DefaultBudget,
+		Exploration:   0.7,
+		RolloutRounds: 2,
+		id:            playerID,
+		rng:           rng,
+		fallback:      normal{id: playerID, rng: rng},
+	}
+}
+
+// Act is used when there is no game to search; it plays like a normal bot.
+func (b *ISMCTS) Act(view engine.PlayerViewData, legal []engine.Action) engine.Action {
+	return b.fallback.Act(view, legal)
+}
+
+// node is a tree node, reached by an action of player.
+type node struct {
+	key      string
+	player   string
+	children []*node
+	visits   int
+	avail    int // times the action was legal when its parent was visited
+	reward   float64
+}
+
+func (n *node) child(key string) *node {
+	for _, c := range n.children {
+		if c.key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+// Search picks the action with the most visits after searching g, which
+// must be a determinized copy for the bot's player.
+func (b *ISMCTS) Search(g *engine.Game) engine.Action {
+	legal := b.options(g, b.id)
+	if len(legal) == 0 {
+		return engine.Action{}
+	}
+	if len(legal) == 1 {
+		return legal[0]
+	}
+
+	root := &node{}
+	deadline := time.Now().Add(b.Budget)
+	for i := 0; ; i++ {
+		if b.MaxIterations > 0 && i >= b.MaxIterations {
+			break
+		}
+		if b.Budget > 0 && time.Now().After(deadline) {
+			break
+		}
+		b.iterate(root, g.Determinize(b.id, b.rng.Uint64()))
+	}
+
+	best, most := legal[0], -1
+	for _, a := range legal {
+		if c := root.child(actionKey(g, a)); c != nil && c.visits > most {
+			best, most = a, c.visits
+		}
+	}
+	return best
+}
+
+// iterate runs one selection, expansion, rollout and backup on d.
+func (b *ISMCTS) iterate(root *node, d *engine.Game) {
+	path := []*node{}
+	n := root
+	for d.Phase != engine.PhaseGameOver {
+		actor := actorOf(d)
+		if actor == "" {
+			break
+		}
+		var untried []engine.Action
+		var best *node
+		var bestAction engine.Action
+		bestScore := math.Inf(-1)
+		for _, a := range b.options(d, actor) {
+			c := n.child(actionKey(d, a))
+			if c == nil {
+				untried = append(untried, a)
+				continue
+			}
+			c.avail++
+			score := c.reward/float64(c.visits) + b.Exploration*math.Sqrt(math.Log(float64(c.avail))/float64(c.visits))
+			if score > bestScore {
+				best, bestAction, bestScore = c, a, score
+			}
+		}
+		if len(untried) > 0 {
+			a := untried[b.rng.IntN(len(untried))]
+			c := &node{key: actionKey(d, a), player: actor, avail: 1}
+			n.children = append(n.children, c)
+			if _, err := d.Apply(actor, a); err != nil {
+				return
+			}
+			path = append(path, c)
+			break
+		}
+		if best == nil {
+			break
+		}
+		if _, err := d.Apply(actor, bestAction); err != nil {
+			return
+		}
+		path = append(path, best)
+		n = best
+	}
+
+	rewards := b.rollout(d)
+	for _, c := range path {
+		c.visits++
+		c.reward += rewards[c.player]
+	}
+}
+
+// options lists an actor's distinct actions. A Magician discard of any
+// subset is searched as discarding the whole hand.
+func (b *ISMCTS) options(g *engine.Game, playerID string) []engine.Action {
+	legal := g.LegalActions(playerID)
+	for i := range legal {
+		legal[i].AnySubset = false
+	}
+	return legal
+}
+
+// rollout plays on with a cheap policy and returns each player's reward.
+func (b *ISMCTS) rollout(d *engine.Game) map[string]float64 {
+	last := d.Round + b.RolloutRounds
+	for steps := 0; d.Phase != engine.PhaseGameOver && d.Round <= last && steps < 2000; steps++ {
+		actor := actorOf(d)
+		if actor == "" {
+			break
+		}
+		if _, err := d.Apply(actor, b.rolloutAction(d, actor)); err != nil {
+			break
+		}
+	}
+	return rewards(d)
+}
+
+// rolloutAction takes gold unless the hand is empty, builds the most
+// expensive card it can, and otherwise plays at random.
+func (b *ISMCTS) rolloutAction(g *engine.Game, playerID string) engine.Action {
+	legal := g.LegalActions(playerID)
+	acts := byType(legal)
+	p := g.GetPlayer(playerID)
+	switch {
+	case len(acts[engine.ActionCollectGold]) > 0:
+		return acts[engine.ActionCollectGold][0]
+	case len(acts[engine.ActionTakeGold]) > 0:
+		if len(p.Hand) == 0 || b.rng.IntN(5) == 0 {
+			return acts[engine.ActionDrawCards][0]
+		}
+		return acts[engine.ActionTakeGold][0]
+	case len(acts[engine.ActionBuild]) > 0:
+		best := acts[engine.ActionBuild][0]
+		for _, a := range acts[engine.ActionBuild] {
+			if cost(p.Hand, a.CardID) > cost(p.Hand, best.CardID) {
+				best = a
+			}
+		}
+		return best
+	case len(acts[engine.ActionAbility]) > 0 && b.rng.IntN(2) == 0:
+		a := acts[engine.ActionAbility][b.rng.IntN(len(acts[engine.ActionAbility]))]
+		a.AnySubset = false
+		return a
+	case len(acts[engine.ActionEndTurn]) > 0:
+		return acts[engine.ActionEndTurn][0]
+	}
+	a := legal[b.rng.IntN(len(legal))]
+	a.AnySubset = false
+	return a
+}
+
+// rewards scores a finished or cut-off game: most of the reward goes to the
+// leader, the rest in proportion to each player's standing, so near misses
+// still count. A cut-off game counts a third of each player's gold.
+func rewards(g *engine.Game) map[string]float64 {
+	entries := g.Scores
+	if g.Phase != engine.PhaseGameOver {
+		entries = g.ProjectedScores("")
+	}
+	value := make(map[string]float64, len(entries))
+	best := 0.0
+	for _, e := range entries {
+		v := float64(e.Total)
+		if g.Phase != engine.PhaseGameOver {
+			v += float64(g.GetPlayer(e.PlayerID).Gold) / 3
+		} else if e.Rank == 1 {
+			v += 0.5 // the tiebreak has been applied
+		}
+		value[e.PlayerID] = v
+		best = math.Max(best, v)
+	}
+	leaders := 0
+	for _, v := range value {
+		if v == best {
+			leaders++
+		}
+	}
+	out := make(map[string]float64, len(value))
+	for id, v := range value {
+		if best > 0 {
+			out[id] = 0.3 * v / best
+		}
+		if v == best {
+			out[id] += 0.7 / float64(leaders)
+		}
+	}
+	return out
+}
+
+// actorOf returns the player who must act next, or "".
+func actorOf(g *engine.Game) string {
+	if d := g.PendingDecision(); d != nil {
+		return d.PlayerID
+	}
+	switch g.Phase {
+	case engine.PhaseDraftPick:
+		return g.Draft.CurrentPickerID()
+	case engine.PhaseDrawChoice, engine.PhasePlayerTurn:
+		return g.CurrentTurnPlayer
+	}
+	return ""
+}
+
+// actionKey names what an action means, independent of card instances.
+func actionKey(g *engine.Game, a engine.Action) string {
+	switch a.Type {
+	case engine.ActionBuild, engine.ActionLabDiscard:
+		return string(a.Type) + " " + a.DistrictName
+	case engine.ActionKeepCard:
+		if a.Index < len(g.DrawnCards) {
+			return string(a.Type) + " " + g.DrawnCards[a.Index].Name
+		}
+	case engine.ActionAbility:
+		return fmt.Sprintf("%s %d %s %s %s %d", a.Type, a.Character, a.Target, a.DistrictName, a.ExtraData, len(a.Indices))
+	}
+	return fmt.Sprintf("%s %d %s %d", a.Type, a.Character, a.ExtraData, a.Index)
+}
+
+// cost returns the cost of the card with the given ID.
+func cost(cards []engine.District, id int) int {
+	for _, d := range cards {
+		if d.ID == id {
+			return d.Cost
+		}
+	}
+	return 0
+}
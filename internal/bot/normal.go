@@ -0,0 +1,241 @@
+package bot
+
+import (
+	"citadels/internal/engine"
+	"math/rand/v2"
+	"slices"
+)
+
+// normal follows simple rules of thumb: pick the character that pays best
+// for its city, keep enough gold to build, build the most expensive card it
+// can, and aim murders, thefts and the Warlord at whoever is ahead.
+type normal struct {
+	id  string
+	rng *rand.Rand
+}
+
+// Rough value of murdering or robbing each character, best first. Merchants
+// and Architects tend to be rich; the King is usually taken.
+var (
+	murderOrder = []engine.CharacterRole{
+		engine.RoleArchitect, engine.RoleMerchant, engine.RoleKing, engine.RoleWarlord,
+		engine.RoleMagician, engine.RoleThief, engine.RoleBishop,
+	}
+	robOrder = []engine.CharacterRole{
+		engine.RoleMerchant, engine.RoleArchitect, engine.RoleKing, engine.RoleBishop,
+		engine.RoleWarlord, engine.RoleMagician,
+	}
+)
+
+func (b *normal) Act(view engine.PlayerViewData, legal []engine.Action) engine.Action {
+	acts := byType(legal)
+	me := self(view, b.id)
+
+	if as := acts[engine.ActionGraveyardRespond]; len(as) > 0 {
+		return b.graveyard(view, me, as)
+	}
+	if as := acts[engine.ActionDraftPick]; len(as) > 0 {
+		return b.draft(view, me, as)
+	}
+	if as := acts[engine.ActionKeepCard]; len(as) > 0 {
+		return b.keep(view, me, as)
+	}
+	if as := acts[engine.ActionCollectGold]; len(as) > 0 {
+		return as[0]
+	}
+
+	role := engine.ParseRole(view.CurrentRole)
+	tookAction := len(acts[engine.ActionTakeGold])+len(acts[engine.ActionDrawCards]) == 0
+
+	// The Assassin, Thief and Magician act before the turn action: what
+	// they do changes what the turn action should be
+	if as := acts[engine.ActionAbility]; len(as) > 0 && role != engine.RoleWarlord {
+		if a, ok := b.ability(view, me, role, as); ok {
+			return a
+		}
+	}
+	if !tookAction {
+		return b.turnAction(view, me, acts)
+	}
+	if as := acts[engine.ActionBuild]; len(as) > 0 {
+		return b.build(view, me, as)
+	}
+	if as := acts[engine.ActionAbility]; len(as) > 0 && role == engine.RoleWarlord {
+		if a, ok := b.warlord(view, as); ok {
+			return a
+		}
+	}
+	if as := acts[engine.ActionLabDiscard]; len(as) > 0 {
+		for _, a := range as {
+			if inCity(me.City, handCard(view, a.CardID)) {
+				return a
+			}
+		}
+	}
+	if as := acts[engine.ActionSmithyDraw]; len(as) > 0 && me.Gold >= 4 && len(view.Hand) <= 1 {
+		return as[0]
+	}
+	if as := acts[engine.ActionEndTurn]; len(as) > 0 {
+		return as[0]
+	}
+	return legal[0]
+}
+
+// draft scores each character for the bot's situation, see draftValues.
+func (b *normal) draft(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) engine.Action {
+	best, bestScore := as[0], -1.0
+	for _, v := range draftValues(view, me, b.id, as) {
+		score := b.rng.Float64() + v.Score // breaks ties
+		if score > bestScore {
+			best, bestScore = v.Action, score
+		}
+	}
+	return best
+}
+
+// turnAction draws when the hand has nothing worth saving for, otherwise
+// takes gold.
+func (b *normal) turnAction(view engine.PlayerViewData, me engine.PublicPlayerData, acts map[engine.ActionType][]engine.Action) engine.Action {
+	wanted := 0
+	for _, d := range view.Hand {
+		if !inCity(me.City, d) {
+			wanted++
+		}
+	}
+	if draw := acts[engine.ActionDrawCards]; len(draw) > 0 && (wanted == 0 || wanted == 1 && me.Gold >= 4) {
+		return draw[0]
+	}
+	if gold := acts[engine.ActionTakeGold]; len(gold) > 0 {
+		return gold[0]
+	}
+	return acts[engine.ActionDrawCards][0]
+}
+
+// keep picks the drawn card keepValues likes best.
+func (b *normal) keep(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) engine.Action {
+	return bestOf(keepValues(view, me, as), as[0])
+}
+
+// build picks the card buildValues likes best.
+func (b *normal) build(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) engine.Action {
+	return bestOf(buildValues(view, me, as), as[0])
+}
+
+// ability picks the Assassin's, Thief's or Magician's target. It returns
+// false to skip the ability.
+func (b *normal) ability(view engine.PlayerViewData, me engine.PublicPlayerData, role engine.CharacterRole, as []engine.Action) (engine.Action, bool) {
+	switch role {
+	case engine.RoleAssassin:
+		return byRole(as, murderOrder, view.Characters)
+	case engine.RoleThief:
+		return byRole(as, robOrder, view.Characters)
+	case engine.RoleMagician:
+		return b.magician(view, me, as)
+	}
+	return engine.Action{}, false
+}
+
+// magician swaps with the biggest hand if it beats the bot's own, otherwise
+// redraws cards it can't use.
+func (b *normal) magician(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) (engine.Action, bool) {
+	var swap, discard *engine.Action
+	most := len(view.Hand) + 1
+	for i, a := range as {
+		switch a.ExtraData {
+		case "swap_hand":
+			for _, p := range view.Players {
+				if p.ID == a.Target && p.HandSize > most {
+					swap, most = &as[i], p.HandSize
+				}
+			}
+		case "discard_draw":
+			discard = &as[i]
+		}
+	}
+	if swap != nil {
+		return *swap, true
+	}
+	if discard == nil {
+		return engine.Action{}, false
+	}
+	var useless []int
+	for _, i := range discard.Indices {
+		if d := view.Hand[i]; inCity(me.City, d) || d.Cost > me.Gold+5 {
+			useless = append(useless, i)
+		}
+	}
+	if len(useless) == 0 {
+		return engine.Action{}, false
+	}
+	a := *discard
+	a.Indices, a.AnySubset = useless, false
+	return a, true
+}
+
+// warlord destroys in the biggest city when it's close to ending the game,
+// or anywhere when it's free.
+func (b *normal) warlord(view engine.PlayerViewData, as []engine.Action) (engine.Action, bool) {
+	var worth []Advice
+	for _, v := range warlordValues(view, as) {
+		if v.Reason != "warlord_costly" {
+			worth = append(worth, v)
+		}
+	}
+	return bestOf(worth, engine.Action{}), len(worth) > 0
+}
+
+// graveyard pays to keep a destroyed district the bot hasn't built yet.
+func (b *normal) graveyard(view engine.PlayerViewData, me engine.PublicPlayerData, as []engine.Action) engine.Action {
+	want := "decline"
+	if d := view.Decision; d != nil && d.Card != nil && !inCity(me.City, *d.Card) && me.Gold >= 2 {
+		want = "accept"
+	}
+	for _, a := range as {
+		if a.ExtraData == want {
+			return a
+		}
+	}
+	return as[0]
+}
+
+// byRole returns the action whose character comes earliest in order,
+// leaving out the bot's own characters.
+func byRole(as []engine.Action, order []engine.CharacterRole, mine []string) (engine.Action, bool) {
+	for _, r := range order {
+		if slices.Contains(mine, r.String()) {
+			continue
+		}
+		for _, a := range as {
+			if a.Character == r {
+				return a, true
+			}
+		}
+	}
+	return engine.Action{}, false
+}
+
+// leadingCity returns the size of the biggest city among the other players.
+func leadingCity(view engine.PlayerViewData, id string) int {
+	most := 0
+	for _, p := range view.Players {
+		if p.ID != id && len(p.City) > most {
+			most = len(p.City)
+		}
+	}
+	return most
+}
+
+// bestOf returns the first action with the highest score, or fallback if
+// there are none.
+func bestOf(vs []Advice, fallback engine.Action) engine.Action {
+	best := -1
+	for i, v := range vs {
+		if best < 0 || v.Score > vs[best].Score {
+			best = i
+		}
+	}
+	if best < 0 {
+		return fallback
+	}
+	return vs[best].Action
+}
@@ -9,22 +9,27 @@ func (a Architect) Role() engine.CharacterRole { return engine.RoleArchitect }
 func (a Architect) NeedsTarget() bool          { return false }
 func (a Architect) IsPassive() bool            { return true }
 
-func (a Architect) ValidTargets(g *engine.Game, playerID string) []string {
+func (a Architect) LegalActions(g *engine.Game, playerID string) []engine.Action {
 	return nil
 }
 
 func (a Architect) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
+	return nil, nil
+}
+
+// OnTurnStart draws 2 extra cards.
+func (a Architect) OnTurnStart(g *engine.Game, playerID string) []engine.Event {
 	player := g.GetPlayer(playerID)
-	if player == nil {
-		return nil, engine.ErrPlayerNotFound
-	}
-	// Draw 2 extra cards
 	drawn := g.Deck.Draw(2)
 	player.Hand = append(player.Hand, drawn...)
-	// Build limit is checked in Game logic (3 for architect)
 	return []engine.Event{
-		{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "architect", "extra_cards": len(drawn),
+		{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+			Ability: "architect", ExtraCards: len(drawn),
 		}},
-	}, nil
+	}
+}
+
+// BeforeBuild raises the build limit to 3.
+func (a Architect) BeforeBuild(g *engine.Game, playerID string, check *engine.BuildCheck) {
+	check.Limit = 3
 }
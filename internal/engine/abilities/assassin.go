@@ -9,26 +9,29 @@ func (a Assassin) Role() engine.CharacterRole { return engine.RoleAssassin }
 func (a Assassin) NeedsTarget() bool          { return true }
 func (a Assassin) IsPassive() bool            { return false }
 
-func (a Assassin) ValidTargets(g *engine.Game, playerID string) []string {
-	var targets []string
-	for _, r := range engine.AllRoles() {
+func (a Assassin) LegalActions(g *engine.Game, playerID string) []engine.Action {
+	var actions []engine.Action
+	for _, r := range g.Config.Roster() {
 		if r == engine.RoleAssassin {
 			continue // can't kill self
 		}
-		targets = append(targets, r.String())
+		actions = append(actions, engine.Action{Type: engine.ActionAbility, Character: r})
 	}
-	return targets
+	return actions
 }
 
 func (a Assassin) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
 	targetRole := action.Character
-	if targetRole == engine.RoleAssassin || targetRole < 2 || targetRole > 8 {
-		return nil, engine.ErrInvalidTarget
-	}
 	g.MurderedRole = targetRole
 	return []engine.Event{
-		{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "assassin", "target_role": targetRole.String(),
+		{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+			Ability: "assassin", TargetRole: targetRole.String(),
 		}},
 	}, nil
 }
+
+// OnRoundEnd clears the murder for the next round.
+func (a Assassin) OnRoundEnd(g *engine.Game, playerID string) []engine.Event {
+	g.MurderedRole = 0
+	return nil
+}
@@ -1,6 +1,9 @@
 package abilities
 
-import "citadels/internal/engine"
+import (
+	"citadels/internal/engine"
+	"fmt"
+)
 
 // Bishop (role 5): Collects gold for religious (blue) districts.
 // Protected from Warlord destruction. Both effects are passive.
@@ -10,12 +13,16 @@ func (b Bishop) Role() engine.CharacterRole { return engine.RoleBishop }
 func (b Bishop) NeedsTarget() bool          { return false }
 func (b Bishop) IsPassive() bool            { return true }
 
-func (b Bishop) ValidTargets(g *engine.Game, playerID string) []string {
+func (b Bishop) LegalActions(g *engine.Game, playerID string) []engine.Action {
 	return nil
 }
 
 func (b Bishop) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
-	// Gold collection for religious districts is handled in resolve phase.
-	// Bishop protection from Warlord is checked in Warlord ability.
+	// Gold collection for religious districts is the collect_gold action.
 	return nil, nil
 }
+
+// OnDestroy protects the Bishop's city while the Bishop is alive.
+func (b Bishop) OnDestroy(g *engine.Game, ownerID string, card engine.District) error {
+	return fmt.Errorf("cannot target Bishop's city")
+}
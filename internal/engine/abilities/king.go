@@ -10,23 +10,20 @@ func (k King) Role() engine.CharacterRole { return engine.RoleKing }
 func (k King) NeedsTarget() bool          { return false }
 func (k King) IsPassive() bool            { return true }
 
-func (k King) ValidTargets(g *engine.Game, playerID string) []string {
+func (k King) LegalActions(g *engine.Game, playerID string) []engine.Action {
 	return nil
 }
 
 func (k King) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
-	player := g.GetPlayer(playerID)
-	if player == nil {
-		return nil, engine.ErrPlayerNotFound
-	}
-	// Crown transfer
+	return nil, nil
+}
+
+// OnCall transfers the crown.
+func (k King) OnCall(g *engine.Game, playerID string) []engine.Event {
 	for _, p := range g.Players {
-		p.HasCrown = false
+		p.HasCrown = p.ID == playerID
 	}
-	player.HasCrown = true
-
-	events := []engine.Event{
-		{Type: engine.EventCrownPassed, Player: playerID},
+	return []engine.Event{
+		{Type: engine.EventCrownPassed, Player: playerID, Data: engine.CrownPassedData{}},
 	}
-	return events, nil
 }
@@ -1,9 +1,6 @@
 package abilities
 
-import (
-	"citadels/internal/engine"
-	"sort"
-)
+import "citadels/internal/engine"
 
 // Magician (role 3): Either swap hand with another player,
 // or discard any number of cards and draw that many.
@@ -13,9 +10,29 @@ func (m Magician) Role() engine.CharacterRole { return engine.RoleMagician }
 func (m Magician) NeedsTarget() bool          { return true }
 func (m Magician) IsPassive() bool            { return false }
 
-func (m Magician) ValidTargets(g *engine.Game, playerID string) []string {
-	targets := []string{"swap_hand", "discard_draw"}
-	return targets
+// LegalActions lists a swap with every other player, then one discard entry
+// that accepts any non-empty set of hand indices.
+func (m Magician) LegalActions(g *engine.Game, playerID string) []engine.Action {
+	player := g.GetPlayer(playerID)
+	if player == nil {
+		return nil
+	}
+	var actions []engine.Action
+	for _, p := range g.Players {
+		if p.ID != playerID {
+			actions = append(actions, engine.Action{Type: engine.ActionAbility, ExtraData: "swap_hand", Target: p.ID})
+		}
+	}
+	if n := len(player.Hand); n > 0 {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		actions = append(actions, engine.Action{
+			Type: engine.ActionAbility, ExtraData: "discard_draw", Indices: indices, AnySubset: true,
+		})
+	}
+	return actions
 }
 
 func (m Magician) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
@@ -27,38 +44,19 @@ func (m Magician) Apply(g *engine.Game, playerID string, action engine.Action) (
 	switch action.ExtraData {
 	case "swap_hand":
 		target := g.GetPlayer(action.Target)
-		if target == nil || target.ID == playerID {
-			return nil, engine.ErrInvalidTarget
-		}
 		player.Hand, target.Hand = target.Hand, player.Hand
 		return []engine.Event{
-			{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-				"ability": "magician", "mode": "swap_hand", "target": target.Name,
+			{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+				Ability: "magician", Mode: "swap_hand", Target: target.Name, TargetID: target.ID,
 			}},
 		}, nil
 
 	case "discard_draw":
-		if len(action.Indices) == 0 {
-			return nil, engine.ErrInvalidAction
-		}
-		// Validate indices
-		for _, idx := range action.Indices {
-			if idx < 0 || idx >= len(player.Hand) {
-				return nil, engine.ErrInvalidAction
-			}
-		}
-		// Sort indices descending to remove correctly
-		sorted := make([]int, len(action.Indices))
-		copy(sorted, action.Indices)
-		sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
-		// Remove duplicates
-		seen := map[int]bool{}
+		// Indices are sorted and unique; remove from the end so earlier
+		// indices stay valid
 		var discarded []engine.District
-		for _, idx := range sorted {
-			if seen[idx] {
-				continue
-			}
-			seen[idx] = true
+		for i := len(action.Indices) - 1; i >= 0; i-- {
+			idx := action.Indices[i]
 			discarded = append(discarded, player.Hand[idx])
 			player.Hand = append(player.Hand[:idx], player.Hand[idx+1:]...)
 		}
@@ -68,8 +66,8 @@ func (m Magician) Apply(g *engine.Game, playerID string, action engine.Action) (
 		drawn := g.Deck.Draw(len(discarded))
 		player.Hand = append(player.Hand, drawn...)
 		return []engine.Event{
-			{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-				"ability": "magician", "mode": "discard_draw", "count": len(discarded),
+			{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+				Ability: "magician", Mode: "discard_draw", Count: len(discarded),
 			}},
 		}, nil
 
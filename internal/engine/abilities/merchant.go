@@ -10,19 +10,20 @@ func (m Merchant) Role() engine.CharacterRole { return engine.RoleMerchant }
 func (m Merchant) NeedsTarget() bool          { return false }
 func (m Merchant) IsPassive() bool            { return true }
 
-func (m Merchant) ValidTargets(g *engine.Game, playerID string) []string {
+func (m Merchant) LegalActions(g *engine.Game, playerID string) []engine.Action {
 	return nil
 }
 
 func (m Merchant) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
-	player := g.GetPlayer(playerID)
-	if player == nil {
-		return nil, engine.ErrPlayerNotFound
-	}
-	player.Gold++
+	return nil, nil
+}
+
+// OnTurnStart gives the bonus gold.
+func (m Merchant) OnTurnStart(g *engine.Game, playerID string) []engine.Event {
+	g.GetPlayer(playerID).Gold++
 	return []engine.Event{
-		{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "merchant", "bonus_gold": 1,
+		{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+			Ability: "merchant", BonusGold: 1,
 		}},
-	}, nil
+	}
 }
@@ -0,0 +1,17 @@
+package abilities
+
+import "citadels/internal/engine"
+
+// NewRegistry returns a registry with all eight base characters.
+func NewRegistry() *engine.AbilityRegistry {
+	r := engine.NewAbilityRegistry()
+	r.Register(Assassin{})
+	r.Register(Thief{})
+	r.Register(Magician{})
+	r.Register(King{})
+	r.Register(Bishop{})
+	r.Register(Merchant{})
+	r.Register(Architect{})
+	r.Register(Warlord{})
+	return r
+}
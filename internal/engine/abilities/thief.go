@@ -10,32 +10,32 @@ func (t Thief) Role() engine.CharacterRole { return engine.RoleThief }
 func (t Thief) NeedsTarget() bool          { return true }
 func (t Thief) IsPassive() bool            { return false }
 
-func (t Thief) ValidTargets(g *engine.Game, playerID string) []string {
-	var targets []string
-	for _, r := range engine.AllRoles() {
+func (t Thief) LegalActions(g *engine.Game, playerID string) []engine.Action {
+	var actions []engine.Action
+	for _, r := range g.Config.Roster() {
 		if r == engine.RoleAssassin || r == engine.RoleThief {
 			continue
 		}
 		if r == g.MurderedRole {
 			continue // can't rob murdered character
 		}
-		targets = append(targets, r.String())
+		actions = append(actions, engine.Action{Type: engine.ActionAbility, Character: r})
 	}
-	return targets
+	return actions
 }
 
 func (t Thief) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
 	targetRole := action.Character
-	if targetRole == engine.RoleAssassin || targetRole == engine.RoleThief || targetRole == g.MurderedRole {
-		return nil, engine.ErrInvalidTarget
-	}
-	if targetRole < 1 || targetRole > 8 {
-		return nil, engine.ErrInvalidTarget
-	}
 	g.RobbedRole = targetRole
 	return []engine.Event{
-		{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "thief", "target_role": targetRole.String(),
+		{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+			Ability: "thief", TargetRole: targetRole.String(),
 		}},
 	}, nil
 }
+
+// OnRoundEnd clears the robbery for the next round.
+func (t Thief) OnRoundEnd(g *engine.Game, playerID string) []engine.Event {
+	g.RobbedRole = 0
+	return nil
+}
@@ -1,9 +1,6 @@
 package abilities
 
-import (
-	"citadels/internal/engine"
-	"fmt"
-)
+import "citadels/internal/engine"
 
 // Warlord (role 8): Collects gold for military (red) districts.
 // Can destroy one district in another player's city by paying (cost - 1) gold.
@@ -13,109 +10,44 @@ func (w Warlord) Role() engine.CharacterRole { return engine.RoleWarlord }
 func (w Warlord) NeedsTarget() bool          { return true }
 func (w Warlord) IsPassive() bool            { return false }
 
-func (w Warlord) ValidTargets(g *engine.Game, playerID string) []string {
+func (w Warlord) LegalActions(g *engine.Game, playerID string) []engine.Action {
 	player := g.GetPlayer(playerID)
 	if player == nil {
 		return nil
 	}
-	var targets []string
+	var actions []engine.Action
 	for _, p := range g.Players {
 		if p.ID == playerID {
 			continue
 		}
-		// Can't target Bishop's city
-		if g.PlayerHasActiveRole(p.ID, engine.RoleBishop) {
-			continue
-		}
-		// Can't target player with completed city (7+)
-		if len(p.City) >= g.Config.EndCitySize {
-			continue
-		}
-		hasGreatWall := p.CityHas("Great Wall")
 		for _, d := range p.City {
-			if d.Name == "Keep" {
-				continue // Keep can't be destroyed
+			if g.CanDestroy(p.ID, d) != nil {
+				continue // Bishop, completed city, Keep
 			}
-			cost := d.Cost - 1
-			if hasGreatWall {
-				cost = d.Cost
-			}
-			if cost <= player.Gold {
-				targets = append(targets, fmt.Sprintf("%s:%s", p.ID, d.Name))
+			if engine.DestroyCost(p.City, d) <= player.Gold {
+				actions = append(actions, engine.Action{Type: engine.ActionAbility, Target: p.ID, CardID: d.ID, DistrictName: d.Name})
 			}
 		}
 	}
-	return targets
+	return actions
 }
 
 func (w Warlord) Apply(g *engine.Game, playerID string, action engine.Action) ([]engine.Event, error) {
 	player := g.GetPlayer(playerID)
-	if player == nil {
-		return nil, engine.ErrPlayerNotFound
-	}
 	target := g.GetPlayer(action.Target)
-	if target == nil || target.ID == playerID {
-		return nil, engine.ErrInvalidTarget
-	}
-	// Check Bishop protection
-	if g.PlayerHasActiveRole(target.ID, engine.RoleBishop) {
-		return nil, fmt.Errorf("cannot target Bishop's city")
-	}
-	// Check completed city
-	if len(target.City) >= g.Config.EndCitySize {
-		return nil, fmt.Errorf("cannot target completed city")
-	}
-
-	// Find district
-	idx := -1
-	for i, d := range target.City {
-		if d.Name == action.DistrictName {
-			idx = i
-			break
-		}
-	}
-	if idx == -1 {
-		return nil, engine.ErrInvalidTarget
-	}
-	d := target.City[idx]
-	if d.Name == "Keep" {
-		return nil, fmt.Errorf("Keep cannot be destroyed")
-	}
-
-	cost := d.Cost - 1
-	if target.CityHas("Great Wall") {
-		cost = d.Cost
-	}
-	if cost > player.Gold {
-		return nil, fmt.Errorf("not enough gold to destroy %s (need %d, have %d)", d.Name, cost, player.Gold)
-	}
-
+	d := target.City[engine.IndexOfCard(target.City, action.CardID, action.DistrictName)]
+	cost := engine.DestroyCost(target.City, d)
 	player.Gold -= cost
-	target.City = append(target.City[:idx], target.City[idx+1:]...)
 
 	events := []engine.Event{
-		{Type: engine.EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability":  "warlord",
-			"target":   target.Name,
-			"district": d.Name,
-			"cost":     cost,
+		{Type: engine.EventAbilityUsed, Player: playerID, Data: engine.AbilityUsedData{
+			Ability:  "warlord",
+			Target:   target.Name,
+			TargetID: target.ID,
+			District: d.Name,
+			CardID:   d.ID,
+			Cost:     cost,
 		}},
 	}
-
-	// Graveyard: any player (except the Warlord) who has Graveyard can pay 1 gold
-	// to take the destroyed district into their hand
-	for _, p := range g.Players {
-		if p.ID == playerID {
-			continue // Warlord can't use their own Graveyard
-		}
-		if p.CityHas("Graveyard") && p.Gold >= 1 {
-			g.PendingGraveyard = &engine.GraveyardPending{
-				PlayerID: p.ID,
-				District: d,
-			}
-			break // only one Graveyard can exist
-		}
-	}
-
-	return events, nil
+	return append(events, g.DestroyDistrict(playerID, target.ID, d)...), nil
 }
@@ -24,11 +24,14 @@ type Action struct {
 	Type   ActionType `json:"type"`
 	// Params depend on Type:
 	// draft_pick: Character (CharacterRole)
-	// build: DistrictName
-	// ability: Target (playerID or role), ExtraData
+	// build: CardID
+	// ability: Target (playerID or role), ExtraData; Warlord: Target, CardID
 	// keep_card: Index
-	// lab_discard: DistrictName
+	// lab_discard: CardID
+	// DistrictName is filled in from CardID for display. Older clients may
+	// send only DistrictName; the first matching card is then used.
 	Character    CharacterRole `json:"character,omitempty"`
+	CardID       int           `json:"card_id,omitempty"`
 	DistrictName string        `json:"district_name,omitempty"`
 	Target       string        `json:"target,omitempty"`
 	Index        int           `json:"index,omitempty"`
@@ -36,6 +39,10 @@ type Action struct {
 	ExtraData    string        `json:"extra_data,omitempty"`
 	// Magician: which card indices to discard
 	Indices      []int         `json:"indices,omitempty"`
+	// AnySubset marks a LegalActions entry whose Indices are choices rather
+	// than a selection: any non-empty subset of them matches. Applying the
+	// entry as-is selects all of them.
+	AnySubset bool `json:"-"`
 }
 
 // EventType identifies events emitted by the engine.
@@ -66,7 +73,7 @@ const (
 type Event struct {
 	Type     EventType   `json:"type"`
 	Player   string      `json:"player,omitempty"`
-	Data     interface{} `json:"data,omitempty"`
+	Data     EventData   `json:"data,omitempty"`
 }
 
 // Ability defines a character's special ability.
@@ -76,8 +83,9 @@ type Ability interface {
 	NeedsTarget() bool
 	// IsPassive returns true if the ability triggers automatically.
 	IsPassive() bool
-	// ValidTargets returns valid target identifiers, given game state.
-	ValidTargets(g *Game, playerID string) []string
+	// LegalActions returns every fully specified ability action the player
+	// may take now. Passive abilities return nil.
+	LegalActions(g *Game, playerID string) []Action
 	// Apply executes the ability. Returns events and error.
 	Apply(g *Game, playerID string, action Action) ([]Event, error)
 }
@@ -32,6 +32,16 @@ func (r CharacterRole) String() string {
 	return "Unknown"
 }
 
+// ParseRole is the inverse of String. It returns 0 for an unknown name.
+func ParseRole(name string) CharacterRole {
+	for r, s := range roleNames {
+		if s == name {
+			return r
+		}
+	}
+	return 0
+}
+
 // DistrictColor associated with each character for gold collection.
 func (r CharacterRole) Color() DistrictColor {
 	switch r {
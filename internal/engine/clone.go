@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// Clone returns a deep copy of the game that shares nothing mutable with g.
+// The copy's random source continues from the same state, so applying the
+// same actions to both gives the same results. The ability registry and the
+// config's card pool are shared; neither changes during a game.
+func (g *Game) Clone() *Game {
+	c := *g
+
+	pcg := *g.pcg
+	c.pcg = &pcg
+	c.rng = rand.New(c.pcg)
+	c.Deck = &Deck{cards: slices.Clone(g.Deck.cards), rng: c.rng}
+
+	c.Players = make([]*Player, len(g.Players))
+	for i, p := range g.Players {
+		cp := *p
+		cp.Hand = slices.Clone(p.Hand)
+		cp.City = slices.Clone(p.City)
+		cp.Characters = slices.Clone(p.Characters)
+		c.Players[i] = &cp
+	}
+
+	if g.Draft != nil {
+		ds := *g.Draft
+		ds.Available = slices.Clone(g.Draft.Available)
+		ds.FaceUp = slices.Clone(g.Draft.FaceUp)
+		ds.FaceDown = slices.Clone(g.Draft.FaceDown)
+		ds.PickOrder = slices.Clone(g.Draft.PickOrder)
+		ds.Picks = make(map[string][]CharacterRole, len(g.Draft.Picks))
+		for pid, roles := range g.Draft.Picks {
+			ds.Picks[pid] = slices.Clone(roles)
+		}
+		c.Draft = &ds
+	}
+
+	c.Pending = make([]*Decision, len(g.Pending))
+	for i, d := range g.Pending {
+		cd := *d
+		if d.Card != nil {
+			card := *d.Card
+			cd.Card = &card
+		}
+		cd.Options = slices.Clone(d.Options)
+		c.Pending[i] = &cd
+	}
+
+	c.Discard = slices.Clone(g.Discard)
+	c.DrawnCards = slices.Clone(g.DrawnCards)
+	c.Scores = slices.Clone(g.Scores)
+	if g.stats != nil {
+		c.stats = g.stats.Clone()
+	}
+	c.joinOrder = slices.Clone(g.joinOrder)
+	c.history = slices.Clone(g.history)
+	return &c
+}
+
+// PreviewResult is what an action would do, seen by the acting player.
+type PreviewResult struct {
+	Events []Event        `json:"events"`
+	View   PlayerViewData `json:"view"`
+}
+
+// Preview applies an action to a copy of the game and reports the events and
+// the player's resulting view; g itself is not touched. So that a preview
+// can't be used to peek, the copy's deck and other players' hands are dealt
+// anew from a fresh random source first: cards the player could not see are
+// random stand-ins, and everything else is exact.
+func (g *Game) Preview(playerID string, action Action) (PreviewResult, error) {
+	c := g.Clone()
+	c.redealHidden(playerID, rand.Uint64())
+	events, err := c.Apply(playerID, action)
+	if err != nil {
+		return PreviewResult{}, err
+	}
+	return PreviewResult{Events: events, View: c.ViewFor(playerID)}, nil
+}
+
+// redealHidden reseeds the game and deals the deck and every other player's
+// hand again, keeping hand sizes, so nothing playerID can't see is real.
+func (g *Game) redealHidden(playerID string, seed uint64) {
+	g.pcg = rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)
+	g.rng = rand.New(g.pcg)
+	g.Deck.rng = g.rng
+
+	pool := g.Deck.Cards()
+	for _, p := range g.Players {
+		if p.ID != playerID {
+			pool = append(pool, p.Hand...)
+		}
+	}
+	g.rng.Shuffle(len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+	for _, p := range g.Players {
+		if p.ID != playerID {
+			n := len(p.Hand)
+			p.Hand, pool = pool[:n:n], pool[n:]
+		}
+	}
+	g.Deck.cards = pool
+}
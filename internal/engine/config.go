@@ -1,9 +1,20 @@
 package engine
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrRoster is returned by CheckRoster for a roster that can't be drafted.
+var ErrRoster = errors.New("invalid character roster")
+
 // GameConfig holds configuration for creating a new game.
 type GameConfig struct {
-	Districts     []District    // card pool
-	EndCitySize   int           // number of districts to trigger end game (default 7)
+	Districts   []District      // card pool
+	EndCitySize int             // number of districts to trigger end game (default 7)
+	Seed        uint64          // seeds all shuffles; 0 picks a random seed
+	Roles       []CharacterRole // characters dealt in each draft; nil for all eight
 }
 
 func DefaultConfig() GameConfig {
@@ -12,3 +23,30 @@ func DefaultConfig() GameConfig {
 		EndCitySize: 7,
 	}
 }
+
+// Roster returns the characters dealt in each draft, in calling order.
+func (c GameConfig) Roster() []CharacterRole {
+	if len(c.Roles) == 0 {
+		return AllRoles()
+	}
+	roles := slices.Clone(c.Roles)
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+// CheckRoster reports whether the roster can be drafted by numPlayers
+// players: every character must be known, and one must be left for the
+// face-down card after everyone has picked.
+func (c GameConfig) CheckRoster(numPlayers int) error {
+	roles := c.Roster()
+	for _, r := range roles {
+		if r.String() == "Unknown" {
+			return fmt.Errorf("%w: unknown character %d", ErrRoster, int(r))
+		}
+	}
+	faceDown, _, picks := DraftConfig(numPlayers)
+	if need := faceDown + picks*numPlayers; len(roles) < need {
+		return fmt.Errorf("%w: %d players need %d characters, have %d", ErrRoster, numPlayers, need, len(roles))
+	}
+	return nil
+}
@@ -5,22 +5,43 @@ import "math/rand/v2"
 // Deck is a stack of district cards.
 type Deck struct {
 	cards []District
+	rng   *rand.Rand // nil uses the global source
 }
 
-// NewDeck creates a shuffled deck from the given cards.
+// NewDeck creates a shuffled deck from the given cards. Cards without an ID
+// are numbered from 1 in input order before shuffling, so every physical
+// card in the game has a stable instance ID.
 func NewDeck(cards []District) *Deck {
-	d := &Deck{cards: make([]District, len(cards))}
+	return newDeck(cards, nil)
+}
+
+func newDeck(cards []District, rng *rand.Rand) *Deck {
+	d := &Deck{cards: make([]District, len(cards)), rng: rng}
 	copy(d.cards, cards)
+	for i := range d.cards {
+		if d.cards[i].ID == 0 {
+			d.cards[i].ID = i + 1
+		}
+	}
 	d.Shuffle()
 	return d
 }
 
 func (d *Deck) Shuffle() {
-	rand.Shuffle(len(d.cards), func(i, j int) {
+	shuffle(d.rng, len(d.cards), func(i, j int) {
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	})
 }
 
+// shuffle uses rng, or the global source if rng is nil.
+func shuffle(rng *rand.Rand, n int, swap func(i, j int)) {
+	if rng == nil {
+		rand.Shuffle(n, swap)
+		return
+	}
+	rng.Shuffle(n, swap)
+}
+
 // Draw removes and returns the top n cards. Returns fewer if deck is short.
 func (d *Deck) Draw(n int) []District {
 	if n > len(d.cards) {
@@ -42,6 +63,11 @@ func (d *Deck) Len() int {
 	return len(d.cards)
 }
 
+// Cards returns a copy of the remaining cards, top first.
+func (d *Deck) Cards() []District {
+	return d.Peek(len(d.cards))
+}
+
 // Peek returns top n cards without removing them.
 func (d *Deck) Peek(n int) []District {
 	if n > len(d.cards) {
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+// PossibleCharacters returns, for each opponent who has picked this round,
+// the characters they could hold as far as playerID can tell. Only what
+// playerID is entitled to know goes in: the roster and the face-up cards,
+// the order everyone picks in, the characters playerID was offered each time
+// they picked, and the calls so far — a called character's holder is shown
+// to everyone, and one nobody answers to was never picked.
+func (g *Game) PossibleCharacters(playerID string) map[string][]CharacterRole {
+	d := g.deduce(playerID)
+	if d == nil {
+		return nil
+	}
+	possible := make(map[string]map[CharacterRole]bool)
+	for _, s := range d.slots {
+		possible[s.player] = make(map[CharacterRole]bool)
+	}
+	d.deal(nil, func(deal []CharacterRole) bool {
+		for i, s := range d.slots {
+			possible[s.player][deal[i]] = true
+		}
+		return true
+	})
+
+	out := make(map[string][]CharacterRole, len(possible))
+	for pid, set := range possible {
+		out[pid] = slices.Sorted(maps.Keys(set))
+	}
+	return out
+}
+
+// deduction is what a player knows about this round's picks.
+type deduction struct {
+	picks  []CharacterRole         // every pick so far, in pick order
+	slots  []pickSlot              // opponents' picks
+	shown  []CharacterRole         // characters called for opponents; they must be among their picks
+	sights map[int][]CharacterRole // what the player was offered, by pick order position
+}
+
+// pickSlot is an opponent's pick and the characters it could be.
+type pickSlot struct {
+	player  string
+	index   int // position in the pick order
+	nth     int // index into the opponent's DraftState.Picks
+	options []CharacterRole
+}
+
+// deduce works out what playerID knows about this round's picks. It
+// returns nil outside a round.
+func (g *Game) deduce(playerID string) *deduction {
+	ds := g.Draft
+	if ds == nil || g.Phase == PhaseGameOver || g.GetPlayer(playerID) == nil {
+		return nil
+	}
+
+	// Before pick j, the characters offered were the ones still available
+	// plus picks[j:]
+	d := &deduction{picks: make([]CharacterRole, ds.CurrentPicker), sights: map[int][]CharacterRole{}}
+	nth := make([]int, len(d.picks))
+	n := map[string]int{}
+	for j := range d.picks {
+		pid := ds.PickOrder[j]
+		nth[j] = n[pid]
+		d.picks[j] = ds.Picks[pid][n[pid]]
+		n[pid]++
+	}
+	for j := 0; j <= ds.CurrentPicker && j < len(ds.PickOrder); j++ {
+		if ds.PickOrder[j] == playerID {
+			d.sights[j] = append(slices.Clone(ds.Available), d.picks[j:]...)
+		}
+	}
+	called := func(r CharacterRole) bool { return ds.IsDone() && r <= g.CurrentCallRole }
+
+	// An opponent's pick made before one of playerID's is nothing playerID
+	// was then offered; one made after it is something that was. Nothing
+	// face up, held by playerID or called for someone else qualifies
+	mine := ds.Picks[playerID]
+	for j := range d.picks {
+		pid := ds.PickOrder[j]
+		if pid == playerID {
+			continue
+		}
+		s := pickSlot{player: pid, index: j, nth: nth[j]}
+		for _, r := range g.Config.Roster() {
+			if slices.Contains(ds.FaceUp, r) || slices.Contains(mine, r) || called(r) && g.FindCharacterOwner(r) != pid {
+				continue
+			}
+			fits := true
+			for k, seen := range d.sights {
+				if slices.Contains(seen, r) != (k < j) {
+					fits = false
+				}
+			}
+			if fits {
+				s.options = append(s.options, r)
+			}
+		}
+		d.slots = append(d.slots, s)
+	}
+
+	for _, r := range g.Config.Roster() {
+		if called(r) && !slices.Contains(mine, r) && g.FindCharacterOwner(r) != "" {
+			d.shown = append(d.shown, r)
+		}
+	}
+	return d
+}
+
+// deal tries every way of giving each opponent pick one of its options,
+// all different, that agrees with the calls. It calls fn with each, by
+// slot, until fn returns false. With an rng, the ways are tried in random
+// order.
+func (d *deduction) deal(rng *rand.Rand, fn func(deal []CharacterRole) bool) {
+	deal := make([]CharacterRole, len(d.slots))
+	var try func(i int) bool
+	try = func(i int) bool {
+		if i == len(d.slots) {
+			for _, r := range d.shown {
+				if !slices.Contains(deal, r) {
+					return true
+				}
+			}
+			return fn(deal)
+		}
+		options := d.slots[i].options
+		if rng != nil {
+			options = slices.Clone(options)
+			rng.Shuffle(len(options), func(a, b int) { options[a], options[b] = options[b], options[a] })
+		}
+		for _, r := range options {
+			if slices.Contains(deal[:i], r) {
+				continue
+			}
+			deal[i] = r
+			if !try(i + 1) {
+				return false
+			}
+		}
+		return true
+	}
+	try(0)
+}
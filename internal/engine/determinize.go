@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+)
+
+// Determinize returns a copy of the game as playerID might imagine it: every
+// card they can't see — the deck and other players' hands — is dealt anew,
+// and the characters they can't see are dealt anew in a way that agrees
+// with what they were offered in the draft and the calls since (see
+// PossibleCharacters). What the player can see is kept exactly. The seed, the history and the statistics
+// are dropped, as they would tell the rest.
+//
+// Search-based bots plan on determinized copies, so they never use
+// information a human in the same seat wouldn't have.
+func (g *Game) Determinize(playerID string, seed uint64) *Game {
+	c := g.Clone()
+	c.redealHidden(playerID, seed)
+	c.redealCharacters(playerID)
+	c.Seed, c.Config.Seed = 0, 0
+	c.history, c.stats = nil, nil
+	return c
+}
+
+// redealCharacters deals opponents' picks anew from what they could be, and
+// the characters left over to the face-down cards and the draft. Once
+// playerID has been offered characters, the draft keeps the ones they last
+// saw that nobody has taken since.
+func (g *Game) redealCharacters(playerID string) {
+	d := g.deduce(playerID)
+	if d == nil {
+		return
+	}
+	ds := g.Draft
+	d.deal(g.rng, func(deal []CharacterRole) bool {
+		for i, s := range d.slots {
+			ds.Picks[s.player][s.nth] = deal[i]
+			d.picks[s.index] = deal[i]
+		}
+		return false
+	})
+
+	var rest []CharacterRole
+	for _, r := range g.Config.Roster() {
+		if !slices.Contains(ds.FaceUp, r) && !slices.Contains(d.picks, r) {
+			rest = append(rest, r)
+		}
+	}
+	g.rng.Shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+	if len(d.sights) > 0 {
+		last := slices.Max(slices.Collect(maps.Keys(d.sights)))
+		var available []CharacterRole
+		for _, r := range d.sights[last] {
+			if !slices.Contains(d.picks[last:], r) {
+				available = append(available, r)
+			}
+		}
+		rest = slices.DeleteFunc(rest, func(r CharacterRole) bool { return slices.Contains(available, r) })
+		rest = append(available, rest...)
+	}
+	n := copy(ds.Available, rest)
+	copy(ds.FaceDown, rest[n:])
+
+	if ds.IsDone() {
+		for _, p := range g.Players {
+			p.Characters = ds.Picks[p.ID]
+		}
+	}
+}
@@ -28,13 +28,38 @@ func (c DistrictColor) String() string {
 	return "Unknown"
 }
 
-// District represents a district card.
+// ParseColor is the inverse of String. It returns ColorNone for an unknown
+// name.
+func ParseColor(name string) DistrictColor {
+	for c, s := range colorNames {
+		if s == name {
+			return c
+		}
+	}
+	return ColorNone
+}
+
+// District represents a district card. ID identifies one physical card
+// within a game's deck; Name is for display and rules lookups.
 type District struct {
+	ID    int           `json:"id,omitempty"`
 	Name  string        `json:"name"`
 	Color DistrictColor `json:"color"`
 	Cost  int           `json:"cost"`
 }
 
+// IndexOfCard returns the index of the card with the given instance ID, or -1.
+// If name is not empty it must match as well, which keeps hand-built cards
+// without an ID addressable by name.
+func IndexOfCard(cards []District, id int, name string) int {
+	for i, d := range cards {
+		if d.ID == id && (name == "" || d.Name == name) {
+			return i
+		}
+	}
+	return -1
+}
+
 // BaseDistricts returns the standard 65-card district deck.
 func BaseDistricts() []District {
 	var cards []District
@@ -1,6 +1,9 @@
 package engine
 
-import "math/rand/v2"
+import (
+	"math/rand/v2"
+	"slices"
+)
 
 // DraftState holds the state of the character draft phase.
 type DraftState struct {
@@ -34,14 +37,17 @@ func DraftConfig(numPlayers int) (faceDown int, faceUp int, picksPerPlayer int)
 	}
 }
 
-// SetupDraft initializes a new draft round.
-func SetupDraft(players []*Player) *DraftState {
+// SetupDraft initializes a new draft round with the given characters. A
+// short roster turns fewer characters face up. A nil rng uses the global
+// source.
+func SetupDraft(players []*Player, roles []CharacterRole, rng *rand.Rand) *DraftState {
 	numPlayers := len(players)
 	faceDown, faceUp, picksPerPlayer := DraftConfig(numPlayers)
+	faceUp = max(0, min(faceUp, len(roles)-faceDown-picksPerPlayer*numPlayers))
 
-	roles := AllRoles()
+	roles = slices.Clone(roles)
 	// Shuffle for random face-down/face-up
-	rand.Shuffle(len(roles), func(i, j int) {
+	shuffle(rng, len(roles), func(i, j int) {
 		roles[i], roles[j] = roles[j], roles[i]
 	})
 
@@ -3,9 +3,19 @@ package engine_test
 import (
 	"citadels/internal/engine"
 	"citadels/internal/engine/abilities"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 )
 
+var update = flag.Bool("update", false, "rewrite generated sections of DOCUMENTATION.md")
+
 func newRegistry() *engine.AbilityRegistry {
 	r := engine.NewAbilityRegistry()
 	r.Register(abilities.Assassin{})
@@ -87,6 +97,49 @@ func TestDraftConfig(t *testing.T) {
 	}
 }
 
+func TestRoster(t *testing.T) {
+	cfg := engine.DefaultConfig()
+	cfg.Roles = []engine.CharacterRole{
+		engine.RoleAssassin, engine.RoleThief, engine.RoleMagician, engine.RoleKing,
+		engine.RoleMerchant, engine.RoleArchitect,
+	}
+	if err := cfg.CheckRoster(5); err != nil {
+		t.Errorf("5 players, 6 characters: %v", err)
+	}
+	if err := cfg.CheckRoster(6); !errors.Is(err, engine.ErrRoster) {
+		t.Errorf("6 players, 6 characters: got %v, want ErrRoster", err)
+	}
+
+	// Five players: the face-up card goes, so everyone still gets a pick
+	cfg.Seed = 3
+	g := engine.NewGame(newTestGame(5).Players, cfg, newRegistry())
+	g.StartGame()
+	if len(g.Draft.FaceUp) != 0 || len(g.Draft.Available) != 5 {
+		t.Fatalf("draft: %d face up, %d available", len(g.Draft.FaceUp), len(g.Draft.Available))
+	}
+	for step := 0; g.Phase != engine.PhaseGameOver && step < 3000; step++ {
+		for _, p := range g.Players {
+			if legal := g.LegalActions(p.ID); len(legal) > 0 {
+				for _, a := range legal {
+					if a.Type == engine.ActionAbility && a.Character == engine.RoleWarlord {
+						t.Fatalf("step %d: %s may target the Warlord, who isn't dealt", step, p.ID)
+					}
+				}
+				if _, err := g.Apply(p.ID, legal[0]); err != nil {
+					t.Fatalf("step %d: %v", step, err)
+				}
+				break
+			}
+		}
+		if err := g.CheckInvariants(); err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+		if g.Draft != nil && slices.Contains(g.Draft.Available, engine.RoleWarlord) {
+			t.Fatalf("step %d: the Warlord was dealt", step)
+		}
+	}
+}
+
 func TestDraftAndResolve(t *testing.T) {
 	g := newTestGame(4)
 	g.StartGame()
@@ -166,6 +219,186 @@ func TestTakeGoldAndBuild(t *testing.T) {
 	}
 }
 
+func TestLegalActions(t *testing.T) {
+	g := newTestGame(4)
+	g.StartGame()
+
+	p := g.Players[0]
+	p.Gold = 10
+	p.Hand = []engine.District{{Name: "Tavern", Color: engine.ColorTrade, Cost: 1}}
+	p.Characters = []engine.CharacterRole{engine.RoleMerchant}
+	g.Phase = engine.PhasePlayerTurn
+	g.CurrentTurnPlayer = p.ID
+	g.CurrentTurnRole = engine.RoleMerchant
+
+	// Building before taking gold or drawing is not legal
+	build := engine.Action{Type: engine.ActionBuild, DistrictName: "Tavern"}
+	if g.IsLegal(p.ID, build) {
+		t.Error("build should not be legal before the turn action")
+	}
+	if _, err := g.Apply(p.ID, build); err == nil {
+		t.Error("Apply should reject build before the turn action")
+	}
+	if g.ViewFor(p.ID).CanBuild {
+		t.Error("view should not offer build before the turn action")
+	}
+
+	if _, err := g.Apply(p.ID, engine.Action{Type: engine.ActionTakeGold}); err != nil {
+		t.Fatalf("take gold error: %v", err)
+	}
+	if !g.IsLegal(p.ID, build) || !g.ViewFor(p.ID).CanBuild {
+		t.Error("build should be legal after taking gold")
+	}
+
+	// Every legal action is accepted by Apply
+	for _, a := range g.LegalActions(p.ID) {
+		if !g.IsLegal(p.ID, a) {
+			t.Errorf("legal action %+v rejected by IsLegal", a)
+		}
+	}
+
+	// Other players have nothing to do
+	if got := g.LegalActions(g.Players[1].ID); len(got) != 0 {
+		t.Errorf("expected no legal actions for waiting player, got %v", got)
+	}
+}
+
+func TestCardInstanceIDs(t *testing.T) {
+	deck := engine.NewDeck(engine.BaseDistricts())
+	seen := map[int]bool{}
+	for _, d := range deck.Draw(deck.Len()) {
+		if d.ID == 0 || seen[d.ID] {
+			t.Fatalf("card %s has missing or duplicate ID %d", d.Name, d.ID)
+		}
+		seen[d.ID] = true
+	}
+
+	g := newTestGame(2)
+	g.StartGame()
+	p := g.Players[0]
+	p.Gold = 10
+	p.Hand = []engine.District{
+		{ID: 101, Name: "Tavern", Color: engine.ColorTrade, Cost: 1},
+		{ID: 102, Name: "Tavern", Color: engine.ColorTrade, Cost: 1},
+		{ID: 103, Name: "Manor", Color: engine.ColorNoble, Cost: 3},
+	}
+	p.Characters = []engine.CharacterRole{engine.RoleArchitect}
+	p.TookAction = true
+	g.Phase = engine.PhasePlayerTurn
+	g.CurrentTurnPlayer = p.ID
+	g.CurrentTurnRole = engine.RoleArchitect
+
+	// The second Tavern is built by ID, not the first one by name
+	if _, err := g.Apply(p.ID, engine.Action{Type: engine.ActionBuild, CardID: 102}); err != nil {
+		t.Fatalf("build by ID: %v", err)
+	}
+	if len(p.City) != 1 || p.City[0].ID != 102 || p.Hand[0].ID != 101 {
+		t.Fatalf("wrong card built: city %v, hand %v", p.City, p.Hand)
+	}
+
+	// Older clients may still name the card
+	if _, err := g.Apply(p.ID, engine.Action{Type: engine.ActionBuild, DistrictName: "Manor"}); err != nil {
+		t.Fatalf("build by name: %v", err)
+	}
+	if p.City[1].ID != 103 {
+		t.Errorf("expected Manor (103) built, got %v", p.City[1])
+	}
+
+	if _, err := g.Apply(p.ID, engine.Action{Type: engine.ActionBuild, CardID: 999}); err == nil {
+		t.Error("build of unknown card ID should fail")
+	}
+}
+
+func TestGraveyardDecision(t *testing.T) {
+	g := newTestGame(2)
+	g.StartGame()
+	warlord, owner := g.Players[0], g.Players[1]
+	warlord.Gold = 5
+	warlord.Characters = []engine.CharacterRole{engine.RoleWarlord}
+	owner.Gold = 1
+	owner.Characters = []engine.CharacterRole{engine.RoleKing}
+	owner.City = []engine.District{
+		{ID: 201, Name: "Graveyard", Color: engine.ColorSpecial, Cost: 5},
+		{ID: 202, Name: "Tavern", Color: engine.ColorTrade, Cost: 1},
+	}
+	g.Phase = engine.PhasePlayerTurn
+	g.CurrentTurnPlayer = warlord.ID
+	g.CurrentTurnRole = engine.RoleWarlord
+
+	if _, err := g.Apply(warlord.ID, engine.Action{Type: engine.ActionAbility, Target: owner.ID, CardID: 202}); err != nil {
+		t.Fatalf("warlord: %v", err)
+	}
+	d := g.PendingDecision()
+	if d == nil || d.PlayerID != owner.ID || d.Kind != engine.DecisionGraveyard {
+		t.Fatalf("expected graveyard decision for %s, got %+v", owner.ID, d)
+	}
+	if g.Phase != engine.PhaseAbility {
+		t.Errorf("expected Ability phase, got %s", g.Phase)
+	}
+	if got := g.LegalActions(warlord.ID); len(got) != 0 {
+		t.Errorf("active player should be blocked, got %v", got)
+	}
+	if v := g.ViewFor(owner.ID); v.Decision == nil || v.Decision.Card.ID != 202 {
+		t.Errorf("owner view should carry the decision, got %+v", v.Decision)
+	}
+
+	if _, err := g.Apply(owner.ID, engine.Action{Type: engine.ActionGraveyardRespond, ExtraData: "decline"}); err != nil {
+		t.Fatalf("decline: %v", err)
+	}
+	if g.PendingDecision() != nil || g.Phase != engine.PhasePlayerTurn {
+		t.Errorf("expected turn to resume, phase %s", g.Phase)
+	}
+	if !g.IsLegal(warlord.ID, engine.Action{Type: engine.ActionEndTurn}) {
+		t.Error("warlord should be able to end turn after the decision")
+	}
+}
+
+// discountMerchant is a test character that builds for 1 gold less.
+type discountMerchant struct{ abilities.Merchant }
+
+func (discountMerchant) BeforeBuild(g *engine.Game, playerID string, check *engine.BuildCheck) {
+	check.Cost--
+}
+
+func TestAbilityHooks(t *testing.T) {
+	g := newTestGame(2)
+	g.Abilities.Register(discountMerchant{})
+	g.StartGame()
+	a, b := g.Players[0], g.Players[1]
+
+	// BuildHook: the discount applies without touching the game loop
+	a.Gold = 2
+	a.Hand = []engine.District{{ID: 301, Name: "Manor", Color: engine.ColorNoble, Cost: 3}}
+	a.Characters = []engine.CharacterRole{engine.RoleMerchant}
+	a.TookAction = true
+	g.Phase = engine.PhasePlayerTurn
+	g.CurrentTurnPlayer = a.ID
+	g.CurrentTurnRole = engine.RoleMerchant
+	if _, err := g.Apply(a.ID, engine.Action{Type: engine.ActionBuild, CardID: 301}); err != nil {
+		t.Fatalf("discounted build: %v", err)
+	}
+	if a.Gold != 0 {
+		t.Errorf("expected 0 gold after discounted build, got %d", a.Gold)
+	}
+
+	// DestroyHook: a living Bishop protects the city
+	b.Characters = []engine.CharacterRole{engine.RoleBishop}
+	b.City = []engine.District{{ID: 302, Name: "Temple", Color: engine.ColorReligious, Cost: 1}}
+	if g.CanDestroy(b.ID, b.City[0]) == nil {
+		t.Error("Bishop's city should be protected")
+	}
+	b.Murdered = true
+	if err := g.CanDestroy(b.ID, b.City[0]); err != nil {
+		t.Errorf("murdered Bishop should not protect: %v", err)
+	}
+
+	// Target descriptors
+	tgt := g.TargetOf(engine.Action{Type: engine.ActionAbility, Target: b.ID, CardID: 302})
+	if tgt.Kind != engine.TargetDistrict || tgt.Owner != b.ID || tgt.Card == nil || tgt.Card.Name != "Temple" {
+		t.Errorf("unexpected district target %+v", tgt)
+	}
+}
+
 func TestScoring(t *testing.T) {
 	g := newTestGame(2)
 	g.StartGame()
@@ -205,6 +438,72 @@ func TestScoring(t *testing.T) {
 	}
 }
 
+func TestScoringTiebreak(t *testing.T) {
+	g := newTestGame(4)
+	g.StartGame()
+	manor := engine.District{Name: "Manor", Color: engine.ColorNoble, Cost: 3}
+	roles := []engine.CharacterRole{engine.RoleKing, engine.RoleWarlord, engine.RoleKing, engine.RoleThief}
+	for i, p := range g.Players {
+		p.City = []engine.District{manor}
+		p.Characters = []engine.CharacterRole{roles[i]}
+		p.Gold = 1
+	}
+	g.Players[3].City = nil
+	g.Players[2].Gold = 5
+
+	scores := g.CalculateScores()
+	var order []string
+	for _, s := range scores {
+		order = append(order, fmt.Sprintf("%s:%d", s.PlayerID, s.Rank))
+	}
+	// Warlord beats Kings on the tie; the richer King beats the other one
+	ps := g.Players
+	want := []string{ps[1].ID + ":1", ps[2].ID + ":2", ps[0].ID + ":3", ps[3].ID + ":4"}
+	if strings.Join(order, " ") != strings.Join(want, " ") {
+		t.Errorf("ranking: got %v, want %v", order, want)
+	}
+	if w := engine.Winners(scores); len(w) != 1 || w[0] != ps[1].ID {
+		t.Errorf("winners: got %v, want [%s]", w, ps[1].ID)
+	}
+
+	// A full tie shares the rank
+	g.Players[1].Characters = []engine.CharacterRole{engine.RoleKing}
+	g.Players[2].Gold = 1
+	if w := engine.Winners(g.CalculateScores()); len(w) != 3 {
+		t.Errorf("full tie: got winners %v, want three", w)
+	}
+}
+
+func TestScoreProjection(t *testing.T) {
+	g := newTestGame(2)
+	g.StartGame()
+	p, other := g.Players[0], g.Players[1]
+	p.City = []engine.District{
+		{Name: "Map Room", Color: engine.ColorSpecial, Cost: 5},
+		{Name: "Manor", Color: engine.ColorNoble, Cost: 3},
+	}
+	hand := len(p.Hand)
+
+	find := func(entries []engine.ScoreEntry) engine.ScoreEntry {
+		for _, e := range entries {
+			if e.PlayerID == p.ID {
+				return e
+			}
+		}
+		t.Fatalf("no projection for %s", p.ID)
+		return engine.ScoreEntry{}
+	}
+	if e := find(g.PublicView().Projection); e.Total != 8 || e.Colors != 2 {
+		t.Errorf("public projection: got total %d, colors %d; want 8, 2", e.Total, e.Colors)
+	}
+	if e := find(g.ViewFor(other.ID).Projection); e.Total != 8 {
+		t.Errorf("opponent sees Map Room bonus: total %d", e.Total)
+	}
+	if e := find(g.ViewFor(p.ID).Projection); e.Total != 8+hand {
+		t.Errorf("owner projection: got %d, want %d", e.Total, 8+hand)
+	}
+}
+
 func TestDeck(t *testing.T) {
 	cards := []engine.District{
 		{Name: "A", Cost: 1},
@@ -245,3 +544,463 @@ func TestCharacterRoleString(t *testing.T) {
 		t.Errorf("RoleKing.String() = %s", engine.RoleKing.String())
 	}
 }
+
+func TestEventDataRoundTrip(t *testing.T) {
+	for _, typ := range engine.EventTypes() {
+		data, ok := engine.NewEventData(typ)
+		if !ok {
+			t.Fatalf("no payload registered for %s", typ)
+		}
+		if data.EventType() != typ {
+			t.Errorf("%T reports %s, registered as %s", data, data.EventType(), typ)
+		}
+	}
+
+	ev := engine.Event{Type: engine.EventRobbed, Player: "B", Data: engine.RobbedData{
+		Role: "King", Stolen: 5, Thief: "Player1", ThiefID: "A",
+	}}
+	raw, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded engine.Event
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	robbed, err := engine.DecodeData[engine.RobbedData](decoded)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if robbed != ev.Data {
+		t.Errorf("round trip: got %+v, want %+v", robbed, ev.Data)
+	}
+	if _, err := engine.DecodeData[engine.GoldTakenData](decoded); err == nil {
+		t.Error("decoding robbed event as gold_taken should fail")
+	}
+}
+
+func TestPhaseTransitions(t *testing.T) {
+	if engine.CanTransition(engine.PhaseLobby, engine.PhasePlayerTurn) {
+		t.Error("Lobby -> PlayerTurn should be illegal")
+	}
+	if !engine.CanTransition(engine.PhaseResolution, engine.PhaseGameOver) {
+		t.Error("Resolution -> GameOver should be legal")
+	}
+
+	// Every phase change of a game start is reported as an event
+	g := newTestGame(4)
+	events := g.StartGame()
+	var changes []string
+	for _, ev := range events {
+		if ev.Type == engine.EventPhaseChange {
+			changes = append(changes, ev.Data.(engine.PhaseChangeData).Phase)
+		}
+	}
+	if len(changes) != 1 || changes[0] != "DraftPick" {
+		t.Errorf("expected one phase change to DraftPick, got %v", changes)
+	}
+}
+
+func TestSeededGame(t *testing.T) {
+	deal := func() []engine.District {
+		g := newSeededGame(4, 99)
+		g.StartGame()
+		return g.Deck.Cards()
+	}
+	a, b := deal(), deal()
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			t.Fatalf("same seed gave different decks at %d: #%d vs #%d", i, a[i].ID, b[i].ID)
+		}
+	}
+	if err := newSeededGame(4, 99).CheckInvariants(); err != nil {
+		t.Errorf("fresh game: %v", err)
+	}
+}
+
+func TestCloneAndPreview(t *testing.T) {
+	g := newSeededGame(4, 5)
+	g.StartGame()
+	for g.Phase == engine.PhaseDraftPick {
+		pid := g.Draft.CurrentPickerID()
+		g.Apply(pid, g.LegalActions(pid)[0])
+	}
+	pid := g.CurrentTurnPlayer
+	before, _ := json.Marshal(g.ViewFor(pid))
+
+	// A clone plays on without touching the original, and with the same
+	// random source continues exactly like it would
+	c := g.Clone()
+	c.Apply(pid, engine.Action{Type: engine.ActionDrawCards})
+	c.GetPlayer(pid).Gold = 99
+	if after, _ := json.Marshal(g.ViewFor(pid)); string(after) != string(before) {
+		t.Error("changing the clone changed the original")
+	}
+	if err := c.CheckInvariants(); err != nil {
+		t.Errorf("clone: %v", err)
+	}
+	g.Apply(pid, engine.Action{Type: engine.ActionDrawCards})
+	if a, b := g.Deck.Cards(), c.Deck.Cards(); len(a) != len(b) || a[0].ID != b[0].ID {
+		t.Error("clone and original diverged after the same action")
+	}
+
+	g = newSeededGame(4, 5)
+	g.StartGame()
+	picker := g.Draft.CurrentPickerID()
+	pick := g.LegalActions(picker)[0]
+	res, err := g.Preview(picker, pick)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if len(res.Events) == 0 || res.Events[0].Type != engine.EventDraftPick {
+		t.Errorf("preview events: %v", res.Events)
+	}
+	if g.Draft.CurrentPicker != 0 {
+		t.Error("preview changed the game")
+	}
+	if _, err := g.Preview(picker, engine.Action{Type: engine.ActionEndTurn}); err == nil {
+		t.Error("preview of an illegal action should fail")
+	}
+}
+
+func TestDeterminize(t *testing.T) {
+	g := newSeededGame(4, 9)
+	g.StartGame()
+	for g.Phase == engine.PhaseDraftPick {
+		pid := g.Draft.CurrentPickerID()
+		g.Apply(pid, g.LegalActions(pid)[0])
+
+		// Mid-draft, for someone who isn't picking
+		if other := g.Draft.PickOrder[len(g.Draft.PickOrder)-1]; other != g.Draft.CurrentPickerID() {
+			if err := g.Determinize(other, 1).CheckInvariants(); err != nil {
+				t.Fatalf("during the draft: %v", err)
+			}
+		}
+	}
+	pid := g.CurrentTurnPlayer
+	view, _ := json.Marshal(g.ViewFor(pid))
+
+	differs := false
+	for seed := uint64(1); seed <= 5; seed++ {
+		d := g.Determinize(pid, seed)
+		if got, _ := json.Marshal(d.ViewFor(pid)); string(got) != string(view) {
+			t.Fatalf("seed %d: the player's view changed", seed)
+		}
+		if err := d.CheckInvariants(); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if d.Seed != 0 || len(d.Moves()) != 0 {
+			t.Errorf("seed %d: the copy keeps the seed or the history", seed)
+		}
+		for i, p := range g.Players {
+			q := d.Players[i]
+			if p.ID != pid && (p.Hand[0].ID != q.Hand[0].ID || !slices.Equal(p.Characters, q.Characters)) {
+				differs = true
+			}
+		}
+		if _, err := d.Apply(pid, engine.Action{Type: engine.ActionDrawCards}); err != nil {
+			t.Errorf("seed %d: the copy doesn't play: %v", seed, err)
+		}
+	}
+	if !differs {
+		t.Error("no determinization changed an opponent's hand or characters")
+	}
+}
+
+func TestPossibleCharacters(t *testing.T) {
+	// Two players pick twice each: the first picker sees exactly what the
+	// other took in between, the second only what was gone before them
+	g := newSeededGame(2, 3)
+	g.StartGame()
+	first := g.Draft.CurrentPickerID()
+	g.Apply(first, g.LegalActions(first)[0])
+	second := g.Draft.CurrentPickerID()
+	offered := slices.Clone(g.Draft.Available)
+	taken := g.LegalActions(second)[0].Character
+	g.Apply(second, g.LegalActions(second)[0])
+
+	if got := g.PossibleCharacters(first)[second]; !slices.Equal(got, []engine.CharacterRole{taken}) {
+		t.Errorf("first picker: %v could be %v, want %v", second, got, taken)
+	}
+	got := g.PossibleCharacters(second)[first]
+	if len(got) != 8-len(offered) || slices.ContainsFunc(got, func(r engine.CharacterRole) bool { return slices.Contains(offered, r) }) {
+		t.Errorf("second picker: %v could be %v, offered %v", first, got, offered)
+	}
+	if _, ok := g.ViewFor(first).PossibleCharacters[second]; !ok {
+		t.Error("the view has no possible characters")
+	}
+
+	// Whatever happens, the truth stays possible, and nothing face up or
+	// held by the viewer is
+	for seed := uint64(1); seed <= 20; seed++ {
+		g := newSeededGame(2+int(seed%6), seed)
+		g.StartGame()
+		rng := rand.New(rand.NewPCG(seed, 0))
+		for step := 0; step < 300 && g.Phase != engine.PhaseGameOver; step++ {
+			for _, viewer := range g.Players {
+				possible := g.PossibleCharacters(viewer.ID)
+				for _, p := range g.Players {
+					held := g.Draft.Picks[p.ID]
+					if p.ID == viewer.ID {
+						if _, ok := possible[p.ID]; ok {
+							t.Fatalf("seed %d: %s is told about their own picks", seed, p.ID)
+						}
+						continue
+					}
+					for _, r := range held {
+						if !slices.Contains(possible[p.ID], r) {
+							t.Fatalf("seed %d step %d: %s holds %v, but %s thinks %v", seed, step, p.ID, r, viewer.ID, possible[p.ID])
+						}
+					}
+					for _, r := range possible[p.ID] {
+						called := g.Draft.IsDone() && r <= g.CurrentCallRole && !slices.Contains(held, r)
+						if called || slices.Contains(g.Draft.FaceUp, r) || slices.Contains(g.Draft.Picks[viewer.ID], r) {
+							t.Fatalf("seed %d: %s could hold %v, which %s can see elsewhere", seed, p.ID, r, viewer.ID)
+						}
+					}
+				}
+			}
+			for _, p := range g.Players {
+				if legal := g.LegalActions(p.ID); len(legal) > 0 {
+					g.Apply(p.ID, legal[rng.IntN(len(legal))])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	g := newSeededGame(4, 11)
+	events := g.StartGame()
+	rng := rand.New(rand.NewPCG(11, 0))
+	for steps := 0; g.Phase != engine.PhaseGameOver && steps < 5000; steps++ {
+		for _, p := range g.Players {
+			if legal := g.LegalActions(p.ID); len(legal) > 0 {
+				evs, err := g.Apply(p.ID, legal[rng.IntN(len(legal))])
+				if err != nil {
+					t.Fatalf("apply: %v", err)
+				}
+				events = append(events, evs...)
+				break
+			}
+		}
+	}
+	if g.Phase != engine.PhaseGameOver {
+		t.Fatal("game did not finish")
+	}
+
+	turns, summaries := 0, 0
+	var stats *engine.Stats
+	for _, ev := range events {
+		switch d := ev.Data.(type) {
+		case engine.TurnEndData:
+			turns++
+		case engine.RoundEndData:
+			if d.Summary == nil || d.Summary.Round != d.Round {
+				t.Errorf("round %d: bad summary %+v", d.Round, d.Summary)
+			}
+			summaries++
+		case engine.GameOverData:
+			stats = d.Stats
+		}
+	}
+	if stats == nil {
+		t.Fatal("game_over carries no stats")
+	}
+	if len(stats.Rounds) != g.Round || summaries != g.Round {
+		t.Errorf("rounds: %d summaries, %d in stats, game had %d", summaries, len(stats.Rounds), g.Round)
+	}
+	for _, ps := range stats.Players {
+		turns -= ps.Turns
+		p := g.GetPlayer(ps.PlayerID)
+		if ps.Built-ps.DistrictsLost != len(p.City) {
+			t.Errorf("%s: built %d, lost %d, city has %d", p.ID, ps.Built, ps.DistrictsLost, len(p.City))
+		}
+		if len(ps.Drafted) != g.Round {
+			t.Errorf("%s: drafted in %d rounds, want %d", p.ID, len(ps.Drafted), g.Round)
+		}
+	}
+	if turns != 0 {
+		t.Errorf("turn counts off by %d", turns)
+	}
+}
+
+func TestUndo(t *testing.T) {
+	g := newSeededGame(4, 5)
+	g.StartGame()
+	picker := g.Draft.CurrentPickerID()
+	g.Apply(picker, g.LegalActions(picker)[0])
+	if err := g.CanUndo(picker); !errors.Is(err, engine.ErrUndoRevealed) {
+		t.Errorf("undo of a draft pick: got %v", err)
+	}
+	for g.Phase == engine.PhaseDraftPick {
+		pid := g.Draft.CurrentPickerID()
+		g.Apply(pid, g.LegalActions(pid)[0])
+	}
+
+	pid := g.CurrentTurnPlayer
+	before, _ := json.Marshal(g.ViewFor(pid))
+	moves := len(g.Moves())
+	if _, err := g.Apply(pid, engine.Action{Type: engine.ActionTakeGold}); err != nil {
+		t.Fatalf("take gold: %v", err)
+	}
+	for _, p := range g.Players {
+		if p.ID != pid && !errors.Is(g.CanUndo(p.ID), engine.ErrUndoNotYours) {
+			t.Errorf("%s may undo %s's action", p.ID, pid)
+		}
+	}
+	m, err := g.Undo(pid)
+	if err != nil || m.Action.Type != engine.ActionTakeGold {
+		t.Fatalf("undo: %v, %v", m, err)
+	}
+	if after, _ := json.Marshal(g.ViewFor(pid)); string(after) != string(before) || len(g.Moves()) != moves {
+		t.Error("undo did not restore the state before take_gold")
+	}
+	if err := g.CheckInvariants(); err != nil {
+		t.Errorf("after undo: %v", err)
+	}
+
+	g.Apply(pid, engine.Action{Type: engine.ActionDrawCards})
+	if _, err := g.Undo(pid); !errors.Is(err, engine.ErrUndoRevealed) {
+		t.Errorf("undo of draw_cards: got %v", err)
+	}
+
+	// Ending the turn calls the next character and shows who answers
+	for g.Phase == engine.PhaseDrawChoice {
+		g.Apply(pid, g.LegalActions(pid)[0])
+	}
+	if _, err := g.Apply(pid, engine.Action{Type: engine.ActionEndTurn}); err != nil {
+		t.Fatalf("end turn: %v", err)
+	}
+	if _, err := g.Undo(pid); !errors.Is(err, engine.ErrUndoRevealed) {
+		t.Errorf("undo of end_turn: got %v", err)
+	}
+}
+
+func newSeededGame(n int, seed uint64) *engine.Game {
+	g := newTestGame(n)
+	cfg := engine.DefaultConfig()
+	cfg.Seed = seed
+	return engine.NewGame(g.Players, cfg, newRegistry())
+}
+
+// FuzzApply plays a game from the fuzzer's bytes, mixing legal moves with
+// arbitrary actions from arbitrary players. Apply must accept exactly the
+// legal ones and the game must stay consistent after every call.
+func FuzzApply(f *testing.F) {
+	f.Add(uint64(1), []byte{2})
+	f.Add(uint64(42), []byte{5, 0, 3, 1, 7, 9, 0, 2, 4})
+	f.Add(uint64(7), []byte{7, 4, 4, 0, 1, 2, 3, 4, 5, 6, 0, 0xff, 0x80})
+	f.Fuzz(func(t *testing.T, seed uint64, data []byte) {
+		next := func() int {
+			if len(data) == 0 {
+				return -1
+			}
+			b := data[0]
+			data = data[1:]
+			return int(b)
+		}
+		n := 4
+		if b := next(); b >= 0 {
+			n = 2 + b%6
+		}
+		g := newSeededGame(n, seed|1)
+		g.StartGame()
+		if err := g.CheckInvariants(); err != nil {
+			t.Fatalf("after start: %v", err)
+		}
+		rng := rand.New(rand.NewPCG(seed, 0))
+
+		for step := 0; step < 2000 && g.Phase != engine.PhaseGameOver; step++ {
+			b := next()
+			if b >= 0 && b%4 == 0 {
+				pid, a := fuzzAction(g, next)
+				legal := g.IsLegal(pid, a)
+				if _, err := g.Apply(pid, a); (err == nil) != legal {
+					t.Fatalf("step %d: Apply(%s, %+v) = %v, legal = %v", step, pid, a, err, legal)
+				}
+			} else {
+				pid, legal := "", []engine.Action(nil)
+				for _, p := range g.Players {
+					if legal = g.LegalActions(p.ID); len(legal) > 0 {
+						pid = p.ID
+						break
+					}
+				}
+				if pid == "" {
+					t.Fatalf("step %d: nobody can act in %s", step, g.Phase)
+				}
+				a := legal[rng.IntN(len(legal))]
+				if _, err := g.Apply(pid, a); err != nil {
+					t.Fatalf("step %d: legal action %+v rejected: %v", step, a, err)
+				}
+			}
+			if err := g.CheckInvariants(); err != nil {
+				t.Fatalf("step %d in %s: %v", step, g.Phase, err)
+			}
+		}
+	})
+}
+
+// fuzzAction builds an arbitrary, usually illegal, action from fuzz bytes.
+func fuzzAction(g *engine.Game, next func() int) (string, engine.Action) {
+	types := []engine.ActionType{
+		engine.ActionDraftPick, engine.ActionTakeGold, engine.ActionDrawCards,
+		engine.ActionKeepCard, engine.ActionBuild, engine.ActionAbility,
+		engine.ActionEndTurn, engine.ActionCollectGold, engine.ActionLabDiscard,
+		engine.ActionSmithyDraw, engine.ActionGraveyardRespond, "bogus",
+	}
+	extras := []string{"", "accept", "decline", "swap_hand", "discard_draw", "steal"}
+	pick := func(n int) int {
+		b := next()
+		if b < 0 {
+			return 0
+		}
+		return b % n
+	}
+	pid := g.Players[pick(len(g.Players))].ID
+	a := engine.Action{
+		Type:      types[pick(len(types))],
+		Character: engine.CharacterRole(pick(10)),
+		CardID:    pick(80),
+		Index:     pick(8) - 2,
+		ExtraData: extras[pick(len(extras))],
+	}
+	if t := pick(len(g.Players) + 2); t < len(g.Players) {
+		a.Target = g.Players[t].ID
+	} else if t == len(g.Players) {
+		a.Target = "nobody"
+	}
+	for i := pick(4); i > 0; i-- {
+		a.Indices = append(a.Indices, pick(10)-1)
+	}
+	return pid, a
+}
+
+const docsPath = "../../DOCUMENTATION.md"
+
+// TestPhaseGraphInDocs keeps the state diagram in DOCUMENTATION.md in sync
+// with the engine's transition table.
+func TestPhaseGraphInDocs(t *testing.T) {
+	const begin, end = "<!-- phase-graph:begin -->\n", "<!-- phase-graph:end -->"
+	doc, err := os.ReadFile(docsPath)
+	if err != nil {
+		t.Fatalf("read docs: %v", err)
+	}
+	i, j := strings.Index(string(doc), begin), strings.Index(string(doc), end)
+	if i < 0 || j < i {
+		t.Fatal("phase graph markers not found in DOCUMENTATION.md")
+	}
+	want := "```mermaid\n" + engine.PhaseGraphMermaid() + "```\n"
+	if *update {
+		out := string(doc[:i+len(begin)]) + want + string(doc[j:])
+		if err := os.WriteFile(docsPath, []byte(out), 0o644); err != nil {
+			t.Fatalf("write docs: %v", err)
+		}
+		return
+	}
+	if string(doc[i+len(begin):j]) != want {
+		t.Error("phase graph in DOCUMENTATION.md is stale; run go generate ./internal/engine")
+	}
+}
@@ -0,0 +1,250 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// EventData is the typed payload of an Event. Each EventType has exactly one
+// payload struct; its JSON tags are part of the wire protocol and must not
+// be renamed. Adding optional fields is fine.
+type EventData interface {
+	EventType() EventType
+}
+
+// DraftStartData is the payload of EventDraftStart.
+type DraftStartData struct {
+	Round          int      `json:"round"`
+	FaceUp         []string `json:"face_up"`
+	AvailableCount int      `json:"available_count"`
+}
+
+// DraftPickData is the payload of EventDraftPick.
+type DraftPickData struct {
+	Character string `json:"character"`
+}
+
+// DraftDoneData is the payload of EventDraftDone.
+type DraftDoneData struct{}
+
+// CharacterCallData is the payload of EventCharacterCall.
+type CharacterCallData struct {
+	Role   string `json:"role"`
+	Number int    `json:"number"`
+	Player string `json:"player,omitempty"` // owner's display name, empty if nobody has the role
+}
+
+// MurderedData is the payload of EventMurdered.
+type MurderedData struct {
+	Role string `json:"role"`
+}
+
+// RobbedData is the payload of EventRobbed.
+type RobbedData struct {
+	Role    string `json:"role"`
+	Stolen  int    `json:"stolen"`
+	Thief   string `json:"thief"`    // display name
+	ThiefID string `json:"thief_id"` // player ID
+}
+
+// GoldTakenData is the payload of EventGoldTaken.
+type GoldTakenData struct {
+	Gold int `json:"gold"`
+}
+
+// CardsDrawnData is the payload of EventCardsDrawn.
+type CardsDrawnData struct {
+	Count int `json:"count"`
+	Kept  int `json:"kept"`
+}
+
+// CardKeptData is the payload of EventCardKept.
+type CardKeptData struct {
+	Card District `json:"card"`
+}
+
+// DistrictBuiltData is the payload of EventDistrictBuilt.
+type DistrictBuiltData struct {
+	CardID   int    `json:"card_id"`
+	District string `json:"district"`
+	Cost     int    `json:"cost"`
+	Color    string `json:"color"`
+}
+
+// AbilityUsedData is the payload of EventAbilityUsed. Ability names the
+// character or district that acted; the remaining fields depend on it.
+type AbilityUsedData struct {
+	Ability string `json:"ability"`
+
+	TargetRole string `json:"target_role,omitempty"` // assassin, thief
+	Mode       string `json:"mode,omitempty"`        // magician: "swap_hand" or "discard_draw"
+	Target     string `json:"target,omitempty"`      // magician, warlord: target's display name
+	TargetID   string `json:"target_id,omitempty"`   // magician, warlord: target's player ID
+	Count      int    `json:"count,omitempty"`       // magician: cards exchanged
+	BonusGold  int    `json:"bonus_gold,omitempty"`  // merchant
+	ExtraCards int    `json:"extra_cards,omitempty"` // architect
+	District   string `json:"district,omitempty"`    // warlord, graveyard
+	CardID     int    `json:"card_id,omitempty"`     // warlord, graveyard, laboratory: the card's instance ID
+	Cost       int    `json:"cost,omitempty"`        // warlord: gold paid
+	Discarded  string `json:"discarded,omitempty"`   // laboratory
+	CardsDrawn int    `json:"cards_drawn,omitempty"` // smithy
+	Gold       int    `json:"gold,omitempty"`        // laboratory: gold gained
+	Action     string `json:"action,omitempty"`      // graveyard: "accept" or "decline"
+}
+
+// TurnEndData is the payload of EventTurnEnd.
+type TurnEndData struct {
+	Role string `json:"role"`
+}
+
+// RoundEndData is the payload of EventRoundEnd.
+type RoundEndData struct {
+	Round   int           `json:"round"`
+	Summary *RoundSummary `json:"summary,omitempty"` // filled in from the game's Stats
+}
+
+// CrownPassedData is the payload of EventCrownPassed.
+type CrownPassedData struct{}
+
+// GameOverData is the payload of EventGameOver.
+type GameOverData struct {
+	Scores  []ScoreEntry `json:"scores"`  // ranked, best first
+	Winners []string     `json:"winners"` // player IDs; several only on a full tie
+	Stats   *Stats       `json:"stats,omitempty"`
+}
+
+// PhaseChangeData is the payload of EventPhaseChange.
+type PhaseChangeData struct {
+	Phase string `json:"phase"`
+	Role  string `json:"role,omitempty"`
+}
+
+// DrawChoiceData is the payload of EventDrawChoice.
+type DrawChoiceData struct {
+	Cards []District `json:"cards"`
+	Keep  int        `json:"keep"`
+}
+
+// GoldCollectedData is the payload of EventGoldCollected.
+type GoldCollectedData struct {
+	Color string `json:"color"`
+	Count int    `json:"count"`
+}
+
+func (DraftStartData) EventType() EventType    { return EventDraftStart }
+func (DraftPickData) EventType() EventType     { return EventDraftPick }
+func (DraftDoneData) EventType() EventType     { return EventDraftDone }
+func (CharacterCallData) EventType() EventType { return EventCharacterCall }
+func (MurderedData) EventType() EventType      { return EventMurdered }
+func (RobbedData) EventType() EventType        { return EventRobbed }
+func (GoldTakenData) EventType() EventType     { return EventGoldTaken }
+func (CardsDrawnData) EventType() EventType    { return EventCardsDrawn }
+func (CardKeptData) EventType() EventType      { return EventCardKept }
+func (DistrictBuiltData) EventType() EventType { return EventDistrictBuilt }
+func (AbilityUsedData) EventType() EventType   { return EventAbilityUsed }
+func (TurnEndData) EventType() EventType       { return EventTurnEnd }
+func (RoundEndData) EventType() EventType      { return EventRoundEnd }
+func (CrownPassedData) EventType() EventType   { return EventCrownPassed }
+func (GameOverData) EventType() EventType      { return EventGameOver }
+func (PhaseChangeData) EventType() EventType   { return EventPhaseChange }
+func (DrawChoiceData) EventType() EventType    { return EventDrawChoice }
+func (GoldCollectedData) EventType() EventType { return EventGoldCollected }
+
+// eventDataTypes maps every EventType to a constructor for its payload.
+var eventDataTypes = map[EventType]func() EventData{
+	EventDraftStart:    func() EventData { return &DraftStartData{} },
+	EventDraftPick:     func() EventData { return &DraftPickData{} },
+	EventDraftDone:     func() EventData { return &DraftDoneData{} },
+	EventCharacterCall: func() EventData { return &CharacterCallData{} },
+	EventMurdered:      func() EventData { return &MurderedData{} },
+	EventRobbed:        func() EventData { return &RobbedData{} },
+	EventGoldTaken:     func() EventData { return &GoldTakenData{} },
+	EventCardsDrawn:    func() EventData { return &CardsDrawnData{} },
+	EventCardKept:      func() EventData { return &CardKeptData{} },
+	EventDistrictBuilt: func() EventData { return &DistrictBuiltData{} },
+	EventAbilityUsed:   func() EventData { return &AbilityUsedData{} },
+	EventTurnEnd:       func() EventData { return &TurnEndData{} },
+	EventRoundEnd:      func() EventData { return &RoundEndData{} },
+	EventCrownPassed:   func() EventData { return &CrownPassedData{} },
+	EventGameOver:      func() EventData { return &GameOverData{} },
+	EventPhaseChange:   func() EventData { return &PhaseChangeData{} },
+	EventDrawChoice:    func() EventData { return &DrawChoiceData{} },
+	EventGoldCollected: func() EventData { return &GoldCollectedData{} },
+}
+
+// EventTypes returns all known event types, sorted by name.
+func EventTypes() []EventType {
+	types := make([]EventType, 0, len(eventDataTypes))
+	for t := range eventDataTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// NewEventData returns a zero payload for the given event type.
+func NewEventData(t EventType) (EventData, bool) {
+	ctor, ok := eventDataTypes[t]
+	if !ok {
+		return nil, false
+	}
+	return deref(ctor()), true
+}
+
+// DecodeEventData decodes a JSON payload into the struct for the given type.
+// An empty payload yields the zero struct.
+func DecodeEventData(t EventType, raw []byte) (EventData, error) {
+	ctor, ok := eventDataTypes[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", t)
+	}
+	data := ctor()
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, data); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", t, err)
+		}
+	}
+	return deref(data), nil
+}
+
+// DecodeData returns the payload of e as T, decoding it first if the event
+// came from JSON. It fails if e carries a different event type.
+func DecodeData[T EventData](e Event) (T, error) {
+	var zero T
+	if e.Type != zero.EventType() {
+		return zero, fmt.Errorf("event %s does not carry %T", e.Type, zero)
+	}
+	if d, ok := e.Data.(T); ok {
+		return d, nil
+	}
+	if e.Data == nil {
+		return zero, nil
+	}
+	return zero, fmt.Errorf("event %s carries %T, not %T", e.Type, e.Data, zero)
+}
+
+// UnmarshalJSON decodes an event, giving Data its concrete payload type.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Type   EventType       `json:"type"`
+		Player string          `json:"player,omitempty"`
+		Data   json.RawMessage `json:"data,omitempty"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	data, err := DecodeEventData(raw.Type, raw.Data)
+	if err != nil {
+		return err
+	}
+	e.Type, e.Player, e.Data = raw.Type, raw.Player, data
+	return nil
+}
+
+// deref turns the pointer returned by a constructor into the value type
+// the engine emits, so decoded and emitted events compare equal.
+func deref(d EventData) EventData {
+	return reflect.ValueOf(d).Elem().Interface().(EventData)
+}
@@ -2,9 +2,7 @@ package engine
 
 import (
 	"errors"
-	"fmt"
-	"math/rand"
-	"sort"
+	"math/rand/v2"
 )
 
 var (
@@ -17,12 +15,6 @@ var (
 	ErrAlreadyBuilt   = errors.New("already built a district with that name")
 )
 
-// GraveyardPending tracks a pending Graveyard choice.
-type GraveyardPending struct {
-	PlayerID string
-	District District
-}
-
 // Game holds the entire game state.
 type Game struct {
 	Players   []*Player        `json:"players"`
@@ -30,6 +22,10 @@ type Game struct {
 	Config    GameConfig       `json:"-"`
 	Abilities *AbilityRegistry `json:"-"`
 
+	Seed uint64     `json:"seed"` // seed of rng; with the actions, it reproduces the game
+	pcg  *rand.PCG  // state of rng, copied by Clone
+	rng  *rand.Rand // all shuffles go through this
+
 	Phase            GamePhase    `json:"phase"`
 	Round            int          `json:"round"`
 	CurrentCallRole  CharacterRole `json:"current_call_role"`
@@ -45,26 +41,49 @@ type Game struct {
 	FinalRound      bool   `json:"final_round"`
 	FirstToComplete string `json:"first_to_complete"`
 
+	// Destroyed districts nobody took back
+	Discard []District `json:"-"`
+
 	// Draw choice state
 	DrawnCards []District `json:"-"`
 	DrawCount  int        `json:"-"` // how many cards to keep
 
-	// Graveyard pending
-	PendingGraveyard *GraveyardPending `json:"-"`
+	// Out-of-turn decisions blocking play (see pending.go)
+	Pending     []*Decision `json:"-"`
+	resumePhase GamePhase   // phase to return to once Pending is empty
 
 	Scores []ScoreEntry `json:"scores,omitempty"`
+
+	stats *Stats // fed every event by record, see stats.go
+
+	joinOrder []string // player IDs as passed to NewGame, see history.go
+	history   []Move   // every applied action
+
+	lastRevealed bool // the last action showed hidden information, see undo.go
 }
 
 // NewGame creates a new game with given players and config.
 func NewGame(players []*Player, config GameConfig, abilities *AbilityRegistry) *Game {
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	pcg := rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)
+	rng := rand.New(pcg)
 	g := &Game{
 		Players:   players,
-		Deck:      NewDeck(config.Districts),
+		Deck:      newDeck(config.Districts, rng),
 		Config:    config,
+		Seed:      seed,
+		pcg:       pcg,
+		rng:       rng,
 		Abilities: abilities,
 		Phase:     PhaseLobby,
 		Round:     0,
 	}
+	for _, p := range players {
+		g.joinOrder = append(g.joinOrder, p.ID)
+	}
 	return g
 }
 
@@ -73,7 +92,7 @@ func (g *Game) StartGame() []Event {
 	var events []Event
 
 	// Randomize player order and crown holder
-	rand.Shuffle(len(g.Players), func(i, j int) {
+	g.rng.Shuffle(len(g.Players), func(i, j int) {
 		g.Players[i], g.Players[j] = g.Players[j], g.Players[i]
 	})
 
@@ -86,14 +105,13 @@ func (g *Game) StartGame() []Event {
 	// Random first player gets the crown
 	g.Players[0].HasCrown = true
 
+	g.stats = NewStats(g.Players)
 	events = append(events, g.startDraft()...)
-	return events
+	return g.record(events)
 }
 
 func (g *Game) startDraft() []Event {
 	g.Round++
-	g.MurderedRole = 0
-	g.RobbedRole = 0
 	g.CurrentCallRole = 0
 	g.CurrentTurnPlayer = ""
 	g.CurrentTurnRole = 0
@@ -111,23 +129,39 @@ func (g *Game) startDraft() []Event {
 		p.CollectedGold = false
 	}
 
-	g.Draft = SetupDraft(g.Players)
-	g.Phase = PhaseDraftPick
+	g.Draft = SetupDraft(g.Players, g.Config.Roster(), g.rng)
 
 	return []Event{
-		{Type: EventDraftStart, Data: map[string]interface{}{
-			"round":    g.Round,
-			"face_up":  roleStrings(g.Draft.FaceUp),
-			"available_count": len(g.Draft.Available),
-		}},
-		{Type: EventPhaseChange, Data: map[string]interface{}{
-			"phase": PhaseDraftPick.String(),
+		{Type: EventDraftStart, Data: DraftStartData{
+			Round:          g.Round,
+			FaceUp:         roleStrings(g.Draft.FaceUp),
+			AvailableCount: len(g.Draft.Available),
 		}},
+		g.setPhase(PhaseDraftPick),
 	}
 }
 
-// Apply is the single entry point for player actions.
+// Apply is the single entry point for player actions. The action must be
+// one of LegalActions(playerID); the apply* handlers below only mutate state.
 func (g *Game) Apply(playerID string, action Action) ([]Event, error) {
+	action = g.canonicalAction(playerID, action)
+	if err := g.validate(playerID, action); err != nil {
+		return nil, err
+	}
+	events, err := g.dispatch(playerID, action)
+	if err != nil {
+		return nil, err
+	}
+	g.history = append(g.history, Move{Player: playerID, Action: action})
+	g.lastRevealed = reveals(events)
+	return g.record(events), nil
+}
+
+// dispatch hands a validated action to its handler.
+func (g *Game) dispatch(playerID string, action Action) ([]Event, error) {
+	if g.PendingDecision() != nil {
+		return g.resolveDecision(action), nil
+	}
 	switch action.Type {
 	case ActionDraftPick:
 		return g.applyDraftPick(playerID, action)
@@ -149,24 +183,19 @@ func (g *Game) Apply(playerID string, action Action) ([]Event, error) {
 		return g.applyLabDiscard(playerID, action)
 	case ActionSmithyDraw:
 		return g.applySmithyDraw(playerID)
-	case ActionGraveyardRespond:
-		return g.applyGraveyardRespond(playerID, action)
 	default:
 		return nil, ErrInvalidAction
 	}
 }
 
 func (g *Game) applyDraftPick(playerID string, action Action) ([]Event, error) {
-	if g.Phase != PhaseDraftPick {
-		return nil, ErrWrongPhase
-	}
 	if err := g.Draft.Pick(playerID, action.Character); err != nil {
 		return nil, err
 	}
 
 	events := []Event{
-		{Type: EventDraftPick, Player: playerID, Data: map[string]interface{}{
-			"character": action.Character.String(),
+		{Type: EventDraftPick, Player: playerID, Data: DraftPickData{
+			Character: action.Character.String(),
 		}},
 	}
 
@@ -175,10 +204,10 @@ func (g *Game) applyDraftPick(playerID string, action Action) ([]Event, error) {
 		for _, p := range g.Players {
 			p.Characters = g.Draft.Picks[p.ID]
 		}
-		events = append(events, Event{Type: EventDraftDone})
+		events = append(events, Event{Type: EventDraftDone, Data: DraftDoneData{}})
 
 		// Start resolution
-		g.Phase = PhaseResolution
+		events = append(events, g.setPhase(PhaseResolution))
 		events = append(events, g.resolveNext()...)
 	}
 
@@ -204,7 +233,12 @@ func (g *Game) resolveNext() []Event {
 }
 
 func (g *Game) endRound() []Event {
-	events := []Event{{Type: EventRoundEnd, Data: map[string]interface{}{"round": g.Round}}}
+	events := []Event{{Type: EventRoundEnd, Data: RoundEndData{Round: g.Round}}}
+	for _, role := range AllRoles() {
+		if h, ok := g.ability(role).(RoundEndHook); ok {
+			events = append(events, h.OnRoundEnd(g, g.FindCharacterOwner(role))...)
+		}
+	}
 
 	if g.FinalRound {
 		return g.endGame(events)
@@ -235,48 +269,25 @@ func (g *Game) endRound() []Event {
 }
 
 func (g *Game) endGame(events []Event) []Event {
-	g.Phase = PhaseGameOver
 	g.Scores = g.CalculateScores()
 	events = append(events, Event{
 		Type: EventGameOver,
-		Data: map[string]interface{}{"scores": g.Scores},
-	})
-	events = append(events, Event{
-		Type: EventPhaseChange,
-		Data: map[string]interface{}{"phase": PhaseGameOver.String()},
+		Data: GameOverData{Scores: g.Scores, Winners: Winners(g.Scores)},
 	})
-	return events
+	return append(events, g.setPhase(PhaseGameOver))
 }
 
 func (g *Game) applyTakeGold(playerID string) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
-	if p.TookAction {
-		return nil, fmt.Errorf("already took an action this turn")
-	}
 	p.Gold += 2
 	p.TookAction = true
 	return []Event{
-		{Type: EventGoldTaken, Player: playerID, Data: map[string]interface{}{"gold": 2}},
+		{Type: EventGoldTaken, Player: playerID, Data: GoldTakenData{Gold: 2}},
 	}, nil
 }
 
 func (g *Game) applyDrawCards(playerID string) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
-	if p.TookAction {
-		return nil, fmt.Errorf("already took an action this turn")
-	}
 
 	drawCount := 2
 	keepCount := 1
@@ -297,8 +308,8 @@ func (g *Game) applyDrawCards(playerID string) ([]Event, error) {
 		// Keep all
 		p.Hand = append(p.Hand, drawn...)
 		return []Event{
-			{Type: EventCardsDrawn, Player: playerID, Data: map[string]interface{}{
-				"count": len(drawn), "kept": len(drawn),
+			{Type: EventCardsDrawn, Player: playerID, Data: CardsDrawnData{
+				Count: len(drawn), Kept: len(drawn),
 			}},
 		}, nil
 	}
@@ -306,25 +317,15 @@ func (g *Game) applyDrawCards(playerID string) ([]Event, error) {
 	// Need to choose which card(s) to keep
 	g.DrawnCards = drawn
 	g.DrawCount = keepCount
-	g.Phase = PhaseDrawChoice
 	return []Event{
-		{Type: EventDrawChoice, Player: playerID, Data: map[string]interface{}{
-			"cards": drawn, "keep": keepCount,
+		{Type: EventDrawChoice, Player: playerID, Data: DrawChoiceData{
+			Cards: drawn, Keep: keepCount,
 		}},
+		g.setPhase(PhaseDrawChoice),
 	}, nil
 }
 
 func (g *Game) applyKeepCard(playerID string, action Action) ([]Event, error) {
-	if g.Phase != PhaseDrawChoice {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
-	if action.Index < 0 || action.Index >= len(g.DrawnCards) {
-		return nil, ErrInvalidAction
-	}
-
 	kept := g.DrawnCards[action.Index]
 	p := g.GetPlayer(playerID)
 	p.Hand = append(p.Hand, kept)
@@ -338,60 +339,27 @@ func (g *Game) applyKeepCard(playerID string, action Action) ([]Event, error) {
 
 	g.DrawnCards = nil
 	g.DrawCount = 0
-	g.Phase = PhasePlayerTurn
 
 	return []Event{
-		{Type: EventCardKept, Player: playerID, Data: map[string]interface{}{
-			"card": kept,
-		}},
-		{Type: EventPhaseChange, Data: map[string]interface{}{
-			"phase": PhasePlayerTurn.String(),
+		{Type: EventCardKept, Player: playerID, Data: CardKeptData{
+			Card: kept,
 		}},
+		g.setPhase(PhasePlayerTurn),
 	}, nil
 }
 
 func (g *Game) applyBuild(playerID string, action Action) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
+	card, _ := p.RemoveFromHand(action.CardID, action.DistrictName)
+	cost := g.buildCheck(playerID, card).Cost
 
-	maxBuild := 1
-	if g.CurrentTurnRole == RoleArchitect {
-		maxBuild = 3
-	}
-	if p.BuiltCount >= maxBuild {
-		return nil, fmt.Errorf("already built maximum districts this turn")
-	}
-
-	// Check if district is in hand
-	card, found := p.RemoveFromHand(action.DistrictName)
-	if !found {
-		return nil, fmt.Errorf("card %s not in hand", action.DistrictName)
-	}
-
-	// Check for duplicate in city (except Haunted City)
-	if card.Name != "Haunted City" && p.CityHas(card.Name) {
-		// Put card back
-		p.Hand = append(p.Hand, card)
-		return nil, ErrAlreadyBuilt
-	}
-
-	if card.Cost > p.Gold {
-		p.Hand = append(p.Hand, card)
-		return nil, ErrNotEnoughGold
-	}
-
-	p.Gold -= card.Cost
+	p.Gold -= cost
 	p.City = append(p.City, card)
 	p.BuiltCount++
 
 	events := []Event{
-		{Type: EventDistrictBuilt, Player: playerID, Data: map[string]interface{}{
-			"district": card.Name, "cost": card.Cost, "color": card.Color.String(),
+		{Type: EventDistrictBuilt, Player: playerID, Data: DistrictBuiltData{
+			CardID: card.ID, District: card.Name, Cost: cost, Color: card.Color.String(),
 		}},
 	}
 
@@ -405,24 +373,11 @@ func (g *Game) applyBuild(playerID string, action Action) ([]Event, error) {
 }
 
 func (g *Game) applyAbility(playerID string, action Action) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn && g.Phase != PhaseAbility {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
-	if p.UsedAbility {
-		return nil, fmt.Errorf("already used ability this turn")
-	}
-
 	ability, err := g.Abilities.Get(g.CurrentTurnRole)
 	if err != nil {
 		return nil, err
 	}
-	if ability.IsPassive() {
-		return nil, fmt.Errorf("this character's ability is passive")
-	}
 
 	events, err := ability.Apply(g, playerID, action)
 	if err != nil {
@@ -430,38 +385,18 @@ func (g *Game) applyAbility(playerID string, action Action) ([]Event, error) {
 	}
 	p.UsedAbility = true
 
-	// If we were in Ability phase, return to PlayerTurn
-	if g.Phase == PhaseAbility {
-		g.Phase = PhasePlayerTurn
-		events = append(events, Event{
-			Type: EventPhaseChange,
-			Data: map[string]interface{}{"phase": PhasePlayerTurn.String()},
-		})
-	}
-
 	return events, nil
 }
 
 func (g *Game) applyEndTurn(playerID string) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
-	if g.PendingGraveyard != nil {
-		return nil, fmt.Errorf("waiting for Graveyard response")
-	}
-
 	events := []Event{
-		{Type: EventTurnEnd, Player: playerID, Data: map[string]interface{}{
-			"role": g.CurrentTurnRole.String(),
+		{Type: EventTurnEnd, Player: playerID, Data: TurnEndData{
+			Role: g.CurrentTurnRole.String(),
 		}},
 	}
 
 	g.CurrentTurnPlayer = ""
-	g.PendingGraveyard = nil
-	g.Phase = PhaseResolution
+	events = append(events, g.setPhase(PhaseResolution))
 
 	// Resolve next character
 	events = append(events, g.resolveNext()...)
@@ -469,119 +404,40 @@ func (g *Game) applyEndTurn(playerID string) ([]Event, error) {
 }
 
 func (g *Game) applyCollectGold(playerID string) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
-	if p.CollectedGold {
-		return nil, fmt.Errorf("already collected gold this turn")
-	}
 	color := g.CurrentTurnRole.Color()
-	if color == ColorNone {
-		return nil, fmt.Errorf("this character has no color")
-	}
 	count := p.CityColorCount(color)
-	if count == 0 {
-		return nil, fmt.Errorf("no matching districts")
-	}
 	p.Gold += count
 	p.CollectedGold = true
 	return []Event{
-		{Type: EventGoldCollected, Player: playerID, Data: map[string]interface{}{
-			"color": color.String(), "count": count,
+		{Type: EventGoldCollected, Player: playerID, Data: GoldCollectedData{
+			Color: color.String(), Count: count,
 		}},
 	}, nil
 }
 
 func (g *Game) applyLabDiscard(playerID string, action Action) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
-	if !p.CityHas("Laboratory") {
-		return nil, fmt.Errorf("you don't have Laboratory")
-	}
-	if p.UsedLab {
-		return nil, fmt.Errorf("already used Laboratory this turn")
-	}
-	card, found := p.RemoveFromHand(action.DistrictName)
-	if !found {
-		return nil, fmt.Errorf("card %s not in hand", action.DistrictName)
-	}
+	card, _ := p.RemoveFromHand(action.CardID, action.DistrictName)
 	g.Deck.Return([]District{card})
 	p.Gold += 2
 	p.UsedLab = true
 	return []Event{
-		{Type: EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "laboratory", "discarded": card.Name,
+		{Type: EventAbilityUsed, Player: playerID, Data: AbilityUsedData{
+			Ability: "laboratory", Discarded: card.Name, CardID: card.ID, Gold: 2,
 		}},
 	}, nil
 }
 
 func (g *Game) applySmithyDraw(playerID string) ([]Event, error) {
-	if g.Phase != PhasePlayerTurn {
-		return nil, ErrWrongPhase
-	}
-	if g.CurrentTurnPlayer != playerID {
-		return nil, ErrNotYourTurn
-	}
 	p := g.GetPlayer(playerID)
-	if !p.CityHas("Smithy") {
-		return nil, fmt.Errorf("you don't have Smithy")
-	}
-	if p.UsedSmithy {
-		return nil, fmt.Errorf("already used Smithy this turn")
-	}
-	if p.Gold < 2 {
-		return nil, ErrNotEnoughGold
-	}
 	p.Gold -= 2
 	p.UsedSmithy = true
 	drawn := g.Deck.Draw(3)
 	p.Hand = append(p.Hand, drawn...)
 	return []Event{
-		{Type: EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "smithy", "cards_drawn": len(drawn),
-		}},
-	}, nil
-}
-
-func (g *Game) applyGraveyardRespond(playerID string, action Action) ([]Event, error) {
-	if g.PendingGraveyard == nil {
-		return nil, fmt.Errorf("no pending graveyard choice")
-	}
-	if g.PendingGraveyard.PlayerID != playerID {
-		return nil, fmt.Errorf("graveyard choice is not for you")
-	}
-
-	pending := g.PendingGraveyard
-
-	if action.ExtraData == "accept" {
-		p := g.GetPlayer(playerID)
-		if p.Gold < 1 {
-			return nil, ErrNotEnoughGold
-		}
-		g.PendingGraveyard = nil
-		p.Gold--
-		p.Hand = append(p.Hand, pending.District)
-		return []Event{
-			{Type: EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-				"ability": "graveyard", "district": pending.District.Name, "action": "accept",
-			}},
-		}, nil
-	}
-
-	// Decline
-	g.PendingGraveyard = nil
-	return []Event{
-		{Type: EventAbilityUsed, Player: playerID, Data: map[string]interface{}{
-			"ability": "graveyard", "district": pending.District.Name, "action": "decline",
+		{Type: EventAbilityUsed, Player: playerID, Data: AbilityUsedData{
+			Ability: "smithy", CardsDrawn: len(drawn),
 		}},
 	}, nil
 }
@@ -611,6 +467,8 @@ type PublicViewData struct {
 	DraftPicker     string                 `json:"draft_picker,omitempty"`
 	DraftAvailable  int                    `json:"draft_available,omitempty"`
 	Scores          []ScoreEntry           `json:"scores,omitempty"`
+	Projection      []ScoreEntry           `json:"projection,omitempty"` // running totals, see ProjectedScores
+	Stats           *Stats                 `json:"stats,omitempty"`      // game statistics, at game over
 	DeckSize        int                    `json:"deck_size"`
 	TimerDeadline   int64                  `json:"timer_deadline,omitempty"`
 }
@@ -624,6 +482,9 @@ type PublicPlayerData struct {
 	HasCrown bool   `json:"has_crown"`
 	// Revealed characters (only during resolution after they act)
 	RevealedRoles []string `json:"revealed_roles,omitempty"`
+	// Hidden information, filled in by RevealedView only
+	Hand       []District `json:"hand,omitempty"`
+	Characters []string   `json:"characters,omitempty"`
 }
 
 func (g *Game) PublicView() PublicViewData {
@@ -636,6 +497,12 @@ func (g *Game) PublicView() PublicViewData {
 		Scores:       g.Scores,
 		DeckSize:     g.Deck.Len(),
 	}
+	if g.Phase != PhaseLobby && g.Phase != PhaseGameOver {
+		pv.Projection = g.ProjectedScores("")
+	}
+	if g.Phase == PhaseGameOver {
+		pv.Stats = g.Stats()
+	}
 	if g.MurderedRole > 0 {
 		pv.MurderedRole = g.MurderedRole.String()
 	}
@@ -680,6 +547,21 @@ func (g *Game) PublicView() PublicViewData {
 	return pv
 }
 
+// RevealedView is PublicView with every hand and every character picked so
+// far shown, for replays of finished games. Never send it during play.
+func (g *Game) RevealedView() PublicViewData {
+	pv := g.PublicView()
+	for i, p := range g.Players {
+		pv.Players[i].Hand = p.Hand
+		roles := p.Characters
+		if g.Draft != nil && !g.Draft.IsDone() {
+			roles = g.Draft.Picks[p.ID]
+		}
+		pv.Players[i].Characters = roleStrings(roles)
+	}
+	return pv
+}
+
 // PlayerView returns the game state visible to a specific player.
 type PlayerViewData struct {
 	PublicViewData
@@ -692,18 +574,22 @@ type PlayerViewData struct {
 	DraftChoices []string       `json:"draft_choices,omitempty"`
 	DrawnCards      []District          `json:"drawn_cards,omitempty"`
 	KeepCount       int                 `json:"keep_count,omitempty"`
-	ValidTargets    []string            `json:"valid_targets,omitempty"`
+	ValidTargets    []string            `json:"valid_targets,omitempty"`   // display labels, see TargetLabel
+	AbilityTargets  []Target            `json:"ability_targets,omitempty"` // same targets, structured
 	CanCollectGold    bool              `json:"can_collect_gold,omitempty"`
 	CollectGoldAmount int              `json:"collect_gold_amount,omitempty"`
 	CanUseLab       bool                `json:"can_use_lab,omitempty"`
 	CanUseSmithy    bool                `json:"can_use_smithy,omitempty"`
-	GraveyardChoice *GraveyardChoiceView `json:"graveyard_choice,omitempty"`
+	Decision        *DecisionView       `json:"decision,omitempty"`
+	CanUndo         bool                `json:"can_undo,omitempty"` // may ask the table to undo the last action
+	PossibleCharacters map[string][]string `json:"possible_characters,omitempty"` // by opponent ID, see PossibleCharacters
 }
 
-// GraveyardChoiceView is sent to the player who can use Graveyard.
-type GraveyardChoiceView struct {
-	DistrictName string `json:"district_name"`
-	DistrictCost int    `json:"district_cost"`
+// DecisionView is sent to the player who must answer the pending decision.
+type DecisionView struct {
+	Kind    string    `json:"kind"`
+	Card    *District `json:"card,omitempty"`
+	Options []string  `json:"options"` // extra_data of each option
 }
 
 func (g *Game) ViewFor(playerID string) PlayerViewData {
@@ -715,6 +601,9 @@ func (g *Game) ViewFor(playerID string) PlayerViewData {
 	if p == nil {
 		return pv
 	}
+	if pv.Projection != nil {
+		pv.Projection = g.ProjectedScores(playerID)
+	}
 
 	pv.Hand = p.Hand
 	for _, c := range p.Characters {
@@ -723,28 +612,31 @@ func (g *Game) ViewFor(playerID string) PlayerViewData {
 
 	pv.IsMyTurn = g.CurrentTurnPlayer == playerID
 
-	if pv.IsMyTurn && g.Phase == PhasePlayerTurn {
-		pv.CanTakeAction = !p.TookAction
-		maxBuild := 1
-		if g.CurrentTurnRole == RoleArchitect {
-			maxBuild = 3
-		}
-		pv.CanBuild = p.BuiltCount < maxBuild && p.TookAction
-
-		ability, err := g.Abilities.Get(g.CurrentTurnRole)
-		if err == nil && !ability.IsPassive() && !p.UsedAbility {
+	// All action flags are derived from the legal move list
+	seenTarget := map[string]bool{}
+	for _, a := range g.LegalActions(playerID) {
+		switch a.Type {
+		case ActionDraftPick:
+			// Already sorted by role number = call order
+			pv.DraftChoices = append(pv.DraftChoices, a.Character.String())
+		case ActionTakeGold, ActionDrawCards:
+			pv.CanTakeAction = true
+		case ActionBuild:
+			pv.CanBuild = true
+		case ActionAbility:
 			pv.CanUseAbility = true
-			pv.ValidTargets = ability.ValidTargets(g, playerID)
-		}
-	}
-
-	// Draft choices (sorted by role number = call order)
-	if g.Phase == PhaseDraftPick && g.Draft != nil && g.Draft.CurrentPickerID() == playerID {
-		sorted := make([]CharacterRole, len(g.Draft.Available))
-		copy(sorted, g.Draft.Available)
-		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
-		for _, r := range sorted {
-			pv.DraftChoices = append(pv.DraftChoices, r.String())
+			if label := TargetLabel(a); !seenTarget[label] {
+				seenTarget[label] = true
+				pv.ValidTargets = append(pv.ValidTargets, label)
+				pv.AbilityTargets = append(pv.AbilityTargets, g.TargetOf(a))
+			}
+		case ActionCollectGold:
+			pv.CanCollectGold = true
+			pv.CollectGoldAmount = p.CityColorCount(g.CurrentTurnRole.Color())
+		case ActionLabDiscard:
+			pv.CanUseLab = true
+		case ActionSmithyDraw:
+			pv.CanUseSmithy = true
 		}
 	}
 
@@ -754,33 +646,22 @@ func (g *Game) ViewFor(playerID string) PlayerViewData {
 		pv.KeepCount = g.DrawCount
 	}
 
-	// Collect gold for matching districts (manual action)
-	if pv.IsMyTurn && g.Phase == PhasePlayerTurn && !p.CollectedGold {
-		if color := g.CurrentTurnRole.Color(); color != ColorNone {
-			count := p.CityColorCount(color)
-			if count > 0 {
-				pv.CanCollectGold = true
-				pv.CollectGoldAmount = count
-			}
+	// Pending decision (shown regardless of whose turn it is)
+	if d := g.PendingDecision(); d != nil && d.PlayerID == playerID {
+		dv := &DecisionView{Kind: d.Kind, Card: d.Card}
+		for _, o := range d.Options {
+			dv.Options = append(dv.Options, o.ExtraData)
 		}
+		pv.Decision = dv
 	}
 
-	// Laboratory / Smithy (available during own turn)
-	if pv.IsMyTurn && g.Phase == PhasePlayerTurn {
-		if p.CityHas("Laboratory") && !p.UsedLab && len(p.Hand) > 0 {
-			pv.CanUseLab = true
-		}
-		if p.CityHas("Smithy") && !p.UsedSmithy && p.Gold >= 2 {
-			pv.CanUseSmithy = true
-		}
-	}
+	pv.CanUndo = g.CanUndo(playerID) == nil
 
-	// Graveyard choice (shown regardless of whose turn it is)
-	if g.PendingGraveyard != nil && g.PendingGraveyard.PlayerID == playerID {
-		pv.GraveyardChoice = &GraveyardChoiceView{
-			DistrictName: g.PendingGraveyard.District.Name,
-			DistrictCost: g.PendingGraveyard.District.Cost,
+	for pid, roles := range g.PossibleCharacters(playerID) {
+		if pv.PossibleCharacters == nil {
+			pv.PossibleCharacters = make(map[string][]string)
 		}
+		pv.PossibleCharacters[pid] = roleStrings(roles)
 	}
 
 	return pv
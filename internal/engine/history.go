@@ -0,0 +1,21 @@
+package engine
+
+import "slices"
+
+// Move is one action the engine accepted, in the form it was applied.
+type Move struct {
+	Player string `json:"player"`
+	Action Action `json:"action"`
+}
+
+// Moves returns every action applied so far, in order. Together with the
+// config, the seed and JoinOrder they reproduce the game exactly.
+func (g *Game) Moves() []Move {
+	return slices.Clone(g.history)
+}
+
+// JoinOrder returns the player IDs in the order they were passed to NewGame,
+// before StartGame shuffled the seating.
+func (g *Game) JoinOrder() []string {
+	return slices.Clone(g.joinOrder)
+}
@@ -0,0 +1,152 @@
+package engine
+
+import "fmt"
+
+// Lifecycle hooks. An Ability implements any of these to take part in the
+// game loop without the core knowing about the character.
+
+// CallHook runs when the character is called and is neither murdered nor
+// absent, after any robbery and before the owner's turn starts.
+type CallHook interface {
+	OnCall(g *Game, playerID string) []Event
+}
+
+// TurnStartHook runs once the owner's per-turn state has been reset.
+type TurnStartHook interface {
+	OnTurnStart(g *Game, playerID string) []Event
+}
+
+// BuildHook adjusts the rules for one build during the character's turn.
+type BuildHook interface {
+	BeforeBuild(g *Game, playerID string, check *BuildCheck)
+}
+
+// DestroyHook is asked before a district in the owner's city is destroyed
+// while the owner holds the (living) character. A non-nil error forbids it.
+type DestroyHook interface {
+	OnDestroy(g *Game, ownerID string, card District) error
+}
+
+// RoundEndHook runs for every registered character when a round ends.
+// playerID is the owner, or "" if nobody picked the character.
+type RoundEndHook interface {
+	OnRoundEnd(g *Game, playerID string) []Event
+}
+
+// BuildCheck is what the engine allows for building one card.
+type BuildCheck struct {
+	Card  District
+	Limit int // districts the player may build this turn
+	Cost  int // gold to pay
+}
+
+// buildCheck applies the current character's BuildHook to the default rules.
+func (g *Game) buildCheck(playerID string, card District) BuildCheck {
+	check := BuildCheck{Card: card, Limit: 1, Cost: card.Cost}
+	if h, ok := g.ability(g.CurrentTurnRole).(BuildHook); ok {
+		h.BeforeBuild(g, playerID, &check)
+	}
+	return check
+}
+
+// CanDestroy reports whether a card in the owner's city may be destroyed.
+// Payment is up to the caller.
+func (g *Game) CanDestroy(ownerID string, card District) error {
+	owner := g.GetPlayer(ownerID)
+	if owner == nil {
+		return ErrPlayerNotFound
+	}
+	if len(owner.City) >= g.Config.EndCitySize {
+		return fmt.Errorf("cannot target completed city")
+	}
+	if card.Name == "Keep" {
+		return fmt.Errorf("Keep cannot be destroyed")
+	}
+	if owner.Murdered {
+		return nil
+	}
+	for _, role := range owner.Characters {
+		if h, ok := g.ability(role).(DestroyHook); ok {
+			if err := h.OnDestroy(g, ownerID, card); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DestroyCost is what destroying a card in the city costs: the card's cost
+// minus 1, or its full cost if the city has a Great Wall.
+func DestroyCost(city []District, card District) int {
+	for _, d := range city {
+		if d.Name == "Great Wall" {
+			return card.Cost
+		}
+	}
+	return card.Cost - 1
+}
+
+// DestroyDistrict removes a card from the owner's city and offers it to a
+// Graveyard; if nobody can take it, it goes to the discard pile. The caller
+// has checked CanDestroy.
+func (g *Game) DestroyDistrict(destroyerID, ownerID string, card District) []Event {
+	owner := g.GetPlayer(ownerID)
+	i := IndexOfCard(owner.City, card.ID, card.Name)
+	owner.City = append(owner.City[:i], owner.City[i+1:]...)
+	events := g.OfferGraveyard(destroyerID, card)
+	if events == nil {
+		g.Discard = append(g.Discard, card)
+	}
+	return events
+}
+
+// ability returns the registered ability for role, or nil.
+func (g *Game) ability(role CharacterRole) Ability {
+	a, err := g.Abilities.Get(role)
+	if err != nil {
+		return nil
+	}
+	return a
+}
+
+// TargetKind says what an ability action is aimed at.
+type TargetKind string
+
+const (
+	TargetCharacter TargetKind = "character" // Role
+	TargetPlayer    TargetKind = "player"    // Owner
+	TargetDistrict  TargetKind = "district"  // Card in Owner's city
+	TargetHand      TargetKind = "hand"      // cards from the actor's own hand
+)
+
+// Target is a structured description of an ability target.
+type Target struct {
+	Kind  TargetKind    `json:"kind"`
+	Mode  string        `json:"mode,omitempty"` // ability mode, e.g. "swap_hand"
+	Role  CharacterRole `json:"role,omitempty"`
+	Owner string        `json:"owner,omitempty"` // player ID
+	Card  *District     `json:"card,omitempty"`
+}
+
+// TargetOf describes the target of an ability action. Hand targets leave out
+// the chosen indices, so all discard selections share one descriptor.
+func (g *Game) TargetOf(a Action) Target {
+	t := Target{Mode: a.ExtraData}
+	switch {
+	case a.Character != 0:
+		t.Kind, t.Role = TargetCharacter, a.Character
+	case a.CardID != 0 || a.DistrictName != "":
+		t.Kind, t.Owner = TargetDistrict, a.Target
+		if owner := g.GetPlayer(a.Target); owner != nil {
+			if i := IndexOfCard(owner.City, a.CardID, a.DistrictName); i >= 0 {
+				card := owner.City[i]
+				t.Card = &card
+			}
+		}
+	case a.Target != "":
+		t.Kind, t.Owner = TargetPlayer, a.Target
+	default:
+		t.Kind = TargetHand
+	}
+	return t
+}
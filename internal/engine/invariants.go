@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// CheckInvariants reports every way the game state is inconsistent, or nil.
+// It is meant for tests and fuzzing: a healthy engine never fails it between
+// two Apply calls, whatever the players send.
+func (g *Game) CheckInvariants() error {
+	var errs []error
+	errs = append(errs, g.checkCards()...)
+	errs = append(errs, g.checkPlayers()...)
+	errs = append(errs, g.checkPhase()...)
+	errs = append(errs, g.checkDraft()...)
+	return errors.Join(errs...)
+}
+
+// checkCards verifies that every district card is in exactly one place:
+// the deck, a hand, a city, the draw choice, a pending decision or the
+// discard pile.
+func (g *Game) checkCards() []error {
+	var errs []error
+	seen := make(map[int]string)
+	count := 0
+	add := func(where string, cards ...District) {
+		for _, c := range cards {
+			count++
+			if c.ID == 0 {
+				errs = append(errs, fmt.Errorf("cards: %s in %s has no ID", c.Name, where))
+				continue
+			}
+			if prev, ok := seen[c.ID]; ok {
+				errs = append(errs, fmt.Errorf("cards: #%d %s in both %s and %s", c.ID, c.Name, prev, where))
+				continue
+			}
+			seen[c.ID] = where
+		}
+	}
+
+	add("deck", g.Deck.Cards()...)
+	for _, p := range g.Players {
+		add(p.ID+" hand", p.Hand...)
+		add(p.ID+" city", p.City...)
+	}
+	add("drawn cards", g.DrawnCards...)
+	for _, d := range g.Pending {
+		if d.Card != nil {
+			add(d.Kind+" decision", *d.Card)
+		}
+	}
+	add("discard", g.Discard...)
+
+	if count != len(g.Config.Districts) {
+		errs = append(errs, fmt.Errorf("cards: %d in play, want %d", count, len(g.Config.Districts)))
+	}
+	return errs
+}
+
+// checkPlayers verifies per-player bookkeeping.
+func (g *Game) checkPlayers() []error {
+	var errs []error
+	crowns := 0
+	for _, p := range g.Players {
+		if p.Gold < 0 {
+			errs = append(errs, fmt.Errorf("players: %s has %d gold", p.ID, p.Gold))
+		}
+		if p.HasCrown {
+			crowns++
+		}
+	}
+	if g.Phase != PhaseLobby && crowns != 1 {
+		errs = append(errs, fmt.Errorf("players: %d crowns", crowns))
+	}
+	return errs
+}
+
+// checkPhase verifies that the turn bookkeeping matches the phase.
+func (g *Game) checkPhase() []error {
+	var errs []error
+	switch g.Phase {
+	case PhaseLobby, PhaseDraftPick, PhaseGameOver:
+		if g.CurrentTurnPlayer != "" {
+			errs = append(errs, fmt.Errorf("phase: %s has current player %s", g.Phase, g.CurrentTurnPlayer))
+		}
+	case PhaseResolution:
+		errs = append(errs, fmt.Errorf("phase: stopped in %s", g.Phase))
+	case PhasePlayerTurn, PhaseDrawChoice:
+		p := g.GetPlayer(g.CurrentTurnPlayer)
+		if p == nil {
+			errs = append(errs, fmt.Errorf("phase: %s without a current player", g.Phase))
+		} else if !slices.Contains(p.Characters, g.CurrentTurnRole) {
+			errs = append(errs, fmt.Errorf("phase: %s plays %s without holding it", p.ID, g.CurrentTurnRole))
+		}
+	}
+
+	if g.Phase == PhaseDrawChoice {
+		if g.DrawCount <= 0 || g.DrawCount >= len(g.DrawnCards) {
+			errs = append(errs, fmt.Errorf("phase: keep %d of %d drawn cards", g.DrawCount, len(g.DrawnCards)))
+		}
+	} else if len(g.DrawnCards) > 0 {
+		errs = append(errs, fmt.Errorf("phase: %d drawn cards outside %s", len(g.DrawnCards), PhaseDrawChoice))
+	}
+
+	if (g.Phase == PhaseAbility) != (len(g.Pending) > 0) {
+		errs = append(errs, fmt.Errorf("phase: %s with %d pending decisions", g.Phase, len(g.Pending)))
+	}
+	return errs
+}
+
+// checkDraft verifies that every character is accounted for exactly once and
+// that the picks match the pick order.
+func (g *Game) checkDraft() []error {
+	ds := g.Draft
+	if ds == nil {
+		return nil
+	}
+	var errs []error
+
+	where := make(map[CharacterRole]int)
+	for _, group := range [][]CharacterRole{ds.Available, ds.FaceUp, ds.FaceDown} {
+		for _, r := range group {
+			where[r]++
+		}
+	}
+	picks := 0
+	for _, roles := range ds.Picks {
+		picks += len(roles)
+		for _, r := range roles {
+			where[r]++
+		}
+	}
+	roster := g.Config.Roster()
+	for _, r := range roster {
+		if where[r] != 1 {
+			errs = append(errs, fmt.Errorf("draft: %s appears %d times", r, where[r]))
+		}
+	}
+	if len(where) != len(roster) {
+		errs = append(errs, fmt.Errorf("draft: %d distinct characters, want %d", len(where), len(roster)))
+	}
+
+	if picks != ds.CurrentPicker || ds.CurrentPicker > len(ds.PickOrder) {
+		errs = append(errs, fmt.Errorf("draft: %d picks at picker %d of %d", picks, ds.CurrentPicker, len(ds.PickOrder)))
+	} else {
+		want := make(map[string]int)
+		for _, pid := range ds.PickOrder[:ds.CurrentPicker] {
+			want[pid]++
+		}
+		for pid, roles := range ds.Picks {
+			if len(roles) != want[pid] {
+				errs = append(errs, fmt.Errorf("draft: %s picked %d times, pick order says %d", pid, len(roles), want[pid]))
+			}
+		}
+	}
+
+	for _, p := range g.Players {
+		var want []CharacterRole
+		if ds.IsDone() {
+			want = ds.Picks[p.ID]
+		}
+		if !slices.Equal(p.Characters, want) {
+			errs = append(errs, fmt.Errorf("draft: %s holds %v, picked %v", p.ID, p.Characters, want))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LegalActions returns every fully specified action the player may take now.
+// Apply accepts exactly these actions, and the view flags are derived from
+// them, so this is the single source of truth for what a player can do.
+func (g *Game) LegalActions(playerID string) []Action {
+	return g.legalActions(playerID, "")
+}
+
+// legalActions lists legal actions, restricted to one type unless only is "".
+// Restricting keeps validation cheap: checking an action only builds the
+// entries of its type. Subsets are never enumerated: the Magician's discard
+// is one AnySubset entry listing the whole hand, and sameAction accepts any
+// non-empty subset of it.
+func (g *Game) legalActions(playerID string, only ActionType) []Action {
+	p := g.GetPlayer(playerID)
+	if p == nil {
+		return nil
+	}
+	var out []Action
+	want := func(t ActionType) bool { return only == "" || only == t }
+
+	// A pending decision blocks everything else
+	if d := g.PendingDecision(); d != nil {
+		if d.PlayerID != playerID {
+			return nil
+		}
+		for _, o := range d.Options {
+			if want(o.Type) {
+				out = append(out, o)
+			}
+		}
+		return out
+	}
+
+	switch g.Phase {
+	case PhaseDraftPick:
+		if g.Draft == nil || g.Draft.CurrentPickerID() != playerID || !want(ActionDraftPick) {
+			break
+		}
+		roles := make([]CharacterRole, len(g.Draft.Available))
+		copy(roles, g.Draft.Available)
+		sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+		for _, r := range roles {
+			out = append(out, Action{Type: ActionDraftPick, Character: r})
+		}
+
+	case PhaseDrawChoice:
+		if g.CurrentTurnPlayer != playerID || !want(ActionKeepCard) {
+			break
+		}
+		for i := range g.DrawnCards {
+			out = append(out, Action{Type: ActionKeepCard, Index: i})
+		}
+
+	case PhasePlayerTurn:
+		if g.CurrentTurnPlayer != playerID {
+			break
+		}
+		out = append(out, g.turnActions(p, want)...)
+	}
+	return out
+}
+
+func (g *Game) turnActions(p *Player, want func(ActionType) bool) []Action {
+	var out []Action
+
+	if !p.TookAction {
+		if want(ActionTakeGold) {
+			out = append(out, Action{Type: ActionTakeGold})
+		}
+		if want(ActionDrawCards) {
+			out = append(out, Action{Type: ActionDrawCards})
+		}
+	}
+
+	if want(ActionBuild) && p.TookAction {
+		for _, card := range p.Hand {
+			if g.canBuild(p, card) == nil {
+				out = append(out, Action{Type: ActionBuild, CardID: card.ID, DistrictName: card.Name})
+			}
+		}
+	}
+
+	if want(ActionCollectGold) && !p.CollectedGold {
+		if color := g.CurrentTurnRole.Color(); color != ColorNone && p.CityColorCount(color) > 0 {
+			out = append(out, Action{Type: ActionCollectGold})
+		}
+	}
+
+	if want(ActionAbility) && !p.UsedAbility {
+		if ability, err := g.Abilities.Get(g.CurrentTurnRole); err == nil && !ability.IsPassive() {
+			out = append(out, ability.LegalActions(g, p.ID)...)
+		}
+	}
+
+	if want(ActionLabDiscard) && p.CityHas("Laboratory") && !p.UsedLab {
+		for _, card := range p.Hand {
+			out = append(out, Action{Type: ActionLabDiscard, CardID: card.ID, DistrictName: card.Name})
+		}
+	}
+
+	if want(ActionSmithyDraw) && p.CityHas("Smithy") && !p.UsedSmithy && p.Gold >= 2 {
+		out = append(out, Action{Type: ActionSmithyDraw})
+	}
+
+	if want(ActionEndTurn) {
+		out = append(out, Action{Type: ActionEndTurn})
+	}
+	return out
+}
+
+// canBuild checks whether p could build a card from hand now, apart from
+// having taken the turn action. BuildHooks may change limit and cost.
+func (g *Game) canBuild(p *Player, card District) error {
+	check := g.buildCheck(p.ID, card)
+	if p.BuiltCount >= check.Limit {
+		return fmt.Errorf("already built maximum districts this turn")
+	}
+	if card.Name != "Haunted City" && p.CityHas(card.Name) {
+		return ErrAlreadyBuilt
+	}
+	if check.Cost > p.Gold {
+		return ErrNotEnoughGold
+	}
+	return nil
+}
+
+// IsLegal reports whether Apply would accept the action.
+func (g *Game) IsLegal(playerID string, action Action) bool {
+	action = g.canonicalAction(playerID, action)
+	for _, legal := range g.legalActions(playerID, action.Type) {
+		if sameAction(legal, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate returns nil if the action is legal, otherwise the most specific
+// reason we can give the player.
+func (g *Game) validate(playerID string, action Action) error {
+	if g.IsLegal(playerID, action) {
+		return nil
+	}
+	return g.explain(playerID, action)
+}
+
+// explain turns an illegal action into a helpful error. It only produces
+// messages; legality itself is decided by legalActions.
+func (g *Game) explain(playerID string, action Action) error {
+	p := g.GetPlayer(playerID)
+	if p == nil {
+		return ErrPlayerNotFound
+	}
+
+	if d := g.PendingDecision(); d != nil {
+		if d.PlayerID != playerID {
+			return fmt.Errorf("waiting for another player's %s decision", d.Kind)
+		}
+		for _, o := range d.Options {
+			if o.Type == action.Type {
+				return ErrInvalidAction
+			}
+		}
+		return fmt.Errorf("answer the pending %s decision first", d.Kind)
+	}
+
+	switch action.Type {
+	case ActionDraftPick:
+		if g.Phase != PhaseDraftPick {
+			return ErrWrongPhase
+		}
+		if g.Draft.CurrentPickerID() != playerID {
+			return ErrNotYourTurn
+		}
+		return ErrInvalidAction
+
+	case ActionGraveyardRespond:
+		return fmt.Errorf("no pending graveyard choice")
+
+	case ActionKeepCard:
+		if g.Phase != PhaseDrawChoice {
+			return ErrWrongPhase
+		}
+		if g.CurrentTurnPlayer != playerID {
+			return ErrNotYourTurn
+		}
+		return ErrInvalidAction
+
+	case ActionTakeGold, ActionDrawCards, ActionBuild, ActionAbility, ActionEndTurn,
+		ActionCollectGold, ActionLabDiscard, ActionSmithyDraw:
+		if g.Phase != PhasePlayerTurn {
+			return ErrWrongPhase
+		}
+		if g.CurrentTurnPlayer != playerID {
+			return ErrNotYourTurn
+		}
+	default:
+		return ErrInvalidAction
+	}
+
+	switch action.Type {
+	case ActionTakeGold, ActionDrawCards:
+		return fmt.Errorf("already took an action this turn")
+	case ActionBuild:
+		if !p.TookAction {
+			return fmt.Errorf("take gold or draw cards before building")
+		}
+		i := IndexOfCard(p.Hand, action.CardID, action.DistrictName)
+		if i < 0 {
+			return fmt.Errorf("card %s not in hand", cardRef(action))
+		}
+		if err := g.canBuild(p, p.Hand[i]); err != nil {
+			return err
+		}
+	case ActionAbility:
+		if p.UsedAbility {
+			return fmt.Errorf("already used ability this turn")
+		}
+		if ability, err := g.Abilities.Get(g.CurrentTurnRole); err != nil {
+			return err
+		} else if ability.IsPassive() {
+			return fmt.Errorf("this character's ability is passive")
+		}
+		return ErrInvalidTarget
+	case ActionCollectGold:
+		if p.CollectedGold {
+			return fmt.Errorf("already collected gold this turn")
+		}
+		if g.CurrentTurnRole.Color() == ColorNone {
+			return fmt.Errorf("this character has no color")
+		}
+		return fmt.Errorf("no matching districts")
+	case ActionLabDiscard:
+		if !p.CityHas("Laboratory") {
+			return fmt.Errorf("you don't have Laboratory")
+		}
+		if p.UsedLab {
+			return fmt.Errorf("already used Laboratory this turn")
+		}
+		return fmt.Errorf("card %s not in hand", cardRef(action))
+	case ActionSmithyDraw:
+		if !p.CityHas("Smithy") {
+			return fmt.Errorf("you don't have Smithy")
+		}
+		if p.UsedSmithy {
+			return fmt.Errorf("already used Smithy this turn")
+		}
+		return ErrNotEnoughGold
+	}
+	return ErrInvalidAction
+}
+
+// canonicalAction puts an action in the form produced by legalActions:
+// card references carry both ID and name, and indices are sorted.
+func (g *Game) canonicalAction(playerID string, a Action) Action {
+	a = normalizeAction(a)
+	var cards []District
+	switch a.Type {
+	case ActionBuild, ActionLabDiscard:
+		if p := g.GetPlayer(playerID); p != nil {
+			cards = p.Hand
+		}
+	case ActionAbility:
+		// Warlord: the card is in the target player's city
+		if t := g.GetPlayer(a.Target); t != nil {
+			cards = t.City
+		}
+	}
+	if a.CardID == 0 && a.DistrictName == "" {
+		return a
+	}
+	for _, d := range cards {
+		if (a.CardID != 0 && d.ID == a.CardID) || (a.CardID == 0 && d.Name == a.DistrictName) {
+			a.CardID, a.DistrictName = d.ID, d.Name
+			break
+		}
+	}
+	return a
+}
+
+// normalizeAction sorts and deduplicates Indices.
+func normalizeAction(a Action) Action {
+	if len(a.Indices) > 0 {
+		seen := map[int]bool{}
+		var idx []int
+		for _, i := range a.Indices {
+			if !seen[i] {
+				seen[i] = true
+				idx = append(idx, i)
+			}
+		}
+		sort.Ints(idx)
+		a.Indices = idx
+	}
+	return a
+}
+
+// sameAction compares the parameters that matter for the action's type.
+func sameAction(legal, a Action) bool {
+	if legal.Type != a.Type || legal.Character != a.Character || legal.CardID != a.CardID ||
+		legal.DistrictName != a.DistrictName || legal.Target != a.Target ||
+		legal.Index != a.Index || legal.ExtraData != a.ExtraData {
+		return false
+	}
+	if legal.AnySubset {
+		// Both are sorted and unique, so a is a subset if each index is
+		// found walking forward through legal
+		j := 0
+		for _, idx := range a.Indices {
+			for j < len(legal.Indices) && legal.Indices[j] < idx {
+				j++
+			}
+			if j == len(legal.Indices) || legal.Indices[j] != idx {
+				return false
+			}
+		}
+		return len(a.Indices) > 0
+	}
+	if len(legal.Indices) != len(a.Indices) {
+		return false
+	}
+	for i := range legal.Indices {
+		if legal.Indices[i] != a.Indices[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TargetLabel is the display form of an ability action used in
+// PlayerViewData.ValidTargets: a role name, a Magician mode, or
+// "playerID:cardID" for the Warlord.
+func TargetLabel(a Action) string {
+	switch {
+	case a.Character != 0:
+		return a.Character.String()
+	case a.ExtraData != "":
+		return a.ExtraData
+	case a.CardID != 0 || a.DistrictName != "":
+		return fmt.Sprintf("%s:%d", a.Target, a.CardID)
+	default:
+		return a.Target
+	}
+}
+
+// cardRef names the card an action refers to, for error messages.
+func cardRef(a Action) string {
+	if a.DistrictName != "" {
+		return a.DistrictName
+	}
+	return fmt.Sprintf("#%d", a.CardID)
+}
@@ -0,0 +1,87 @@
+package engine
+
+// Decision kinds.
+const (
+	DecisionGraveyard = "graveyard" // pay 1 gold to take a destroyed district
+)
+
+// Decision is a choice one player must make before play continues, possibly
+// outside their own turn. While any decision is pending the game sits in
+// PhaseAbility and the only legal actions are the head decision's Options.
+type Decision struct {
+	Kind     string    `json:"kind"`
+	PlayerID string    `json:"player_id"`
+	Card     *District `json:"card,omitempty"` // card the decision is about, if any
+	Options  []Action  `json:"options"`
+
+	// Resolve applies the chosen option. It must only use g and d, never
+	// state captured when the decision was queued.
+	Resolve func(g *Game, d *Decision, choice Action) []Event `json:"-"`
+}
+
+// PendingDecision returns the decision that blocks play, or nil.
+func (g *Game) PendingDecision() *Decision {
+	if len(g.Pending) == 0 {
+		return nil
+	}
+	return g.Pending[0]
+}
+
+// QueueDecision adds a decision to the queue. The first decision suspends
+// the current phase until the queue is empty again.
+func (g *Game) QueueDecision(d *Decision) []Event {
+	g.Pending = append(g.Pending, d)
+	if len(g.Pending) > 1 {
+		return nil
+	}
+	g.resumePhase = g.Phase
+	return []Event{g.setPhase(PhaseAbility)}
+}
+
+// resolveDecision applies a choice for the head decision. The choice has
+// already been validated against its Options.
+func (g *Game) resolveDecision(choice Action) []Event {
+	d := g.Pending[0]
+	g.Pending = g.Pending[1:]
+	events := d.Resolve(g, d, choice)
+	if len(g.Pending) == 0 && g.Phase == PhaseAbility {
+		events = append(events, g.setPhase(g.resumePhase))
+	}
+	return events
+}
+
+// OfferGraveyard queues a Graveyard decision for the destroyed district, if
+// a player other than the destroyer owns a Graveyard and can pay for it.
+func (g *Game) OfferGraveyard(destroyerID string, card District) []Event {
+	for _, p := range g.Players {
+		if p.ID == destroyerID || !p.CityHas("Graveyard") || p.Gold < 1 {
+			continue // the Warlord can't use their own Graveyard
+		}
+		return g.QueueDecision(&Decision{ // only one Graveyard can exist
+			Kind:     DecisionGraveyard,
+			PlayerID: p.ID,
+			Card:     &card,
+			Options: []Action{
+				{Type: ActionGraveyardRespond, ExtraData: "accept"},
+				{Type: ActionGraveyardRespond, ExtraData: "decline"},
+			},
+			Resolve: resolveGraveyard,
+		})
+	}
+	return nil
+}
+
+func resolveGraveyard(g *Game, d *Decision, choice Action) []Event {
+	if choice.ExtraData == "accept" {
+		p := g.GetPlayer(d.PlayerID)
+		p.Gold--
+		p.Hand = append(p.Hand, *d.Card)
+	} else {
+		g.Discard = append(g.Discard, *d.Card)
+	}
+	return []Event{
+		{Type: EventAbilityUsed, Player: d.PlayerID, Data: AbilityUsedData{
+			Ability: "graveyard", District: d.Card.Name, CardID: d.Card.ID, Action: choice.ExtraData,
+		}},
+	}
+}
@@ -1,22 +1,27 @@
 package engine
 
+//go:generate go test -run TestPhaseGraphInDocs -update
+
+import (
+	"fmt"
+	"strings"
+)
+
 // GamePhase represents the current phase of the game state machine.
 type GamePhase int
 
 const (
 	PhaseLobby      GamePhase = iota // waiting for players
-	PhaseDraftSetup                  // setting up draft (face-down/face-up cards)
 	PhaseDraftPick                   // players picking characters
 	PhaseResolution                  // calling characters 1-8
 	PhasePlayerTurn                  // active player taking actions
-	PhaseAbility                     // resolving an ability that needs input
+	PhaseAbility                     // waiting on a pending decision (see pending.go)
 	PhaseDrawChoice                  // player choosing which drawn card to keep
 	PhaseGameOver                    // game finished
 )
 
 var phaseNames = map[GamePhase]string{
 	PhaseLobby:      "Lobby",
-	PhaseDraftSetup: "DraftSetup",
 	PhaseDraftPick:  "DraftPick",
 	PhaseResolution: "Resolution",
 	PhasePlayerTurn: "PlayerTurn",
@@ -31,3 +36,81 @@ func (p GamePhase) String() string {
 	}
 	return "Unknown"
 }
+
+// Transition is one allowed edge of the phase state machine.
+type Transition struct {
+	From, To GamePhase
+	On       string // what triggers it, for docs
+}
+
+// phaseTransitions is the complete phase state machine. Every phase change
+// goes through setPhase, which refuses anything not listed here.
+var phaseTransitions = []Transition{
+	{PhaseLobby, PhaseDraftPick, "start game"},
+	{PhaseDraftPick, PhaseResolution, "all characters picked"},
+	{PhaseResolution, PhasePlayerTurn, "character called"},
+	{PhaseResolution, PhaseDraftPick, "round over"},
+	{PhaseResolution, PhaseGameOver, "final round over"},
+	{PhasePlayerTurn, PhaseDrawChoice, "draw cards"},
+	{PhasePlayerTurn, PhaseAbility, "decision queued"},
+	{PhasePlayerTurn, PhaseResolution, "end turn"},
+	{PhaseDrawChoice, PhasePlayerTurn, "card kept"},
+	{PhaseAbility, PhasePlayerTurn, "decisions resolved"},
+}
+
+// Transitions returns the phase state machine's edges.
+func Transitions() []Transition {
+	out := make([]Transition, len(phaseTransitions))
+	copy(out, phaseTransitions)
+	return out
+}
+
+// CanTransition reports whether the state machine allows from -> to.
+func CanTransition(from, to GamePhase) bool {
+	for _, t := range phaseTransitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// setPhase moves the game to a new phase and returns the matching
+// EventPhaseChange. Entering PlayerTurn names the current player and role.
+// An illegal transition is an engine bug, so it panics rather than enter a
+// phase the game can't leave. The server's hub recovers and logs it.
+func (g *Game) setPhase(to GamePhase) Event {
+	if !CanTransition(g.Phase, to) {
+		panic(fmt.Sprintf("engine: illegal phase transition %s -> %s", g.Phase, to))
+	}
+	g.Phase = to
+	ev := Event{Type: EventPhaseChange, Data: PhaseChangeData{Phase: to.String()}}
+	if to == PhasePlayerTurn {
+		ev.Player = g.CurrentTurnPlayer
+		ev.Data = PhaseChangeData{Phase: to.String(), Role: g.CurrentTurnRole.String()}
+	}
+	return ev
+}
+
+// PhaseGraphDOT renders the state machine in Graphviz DOT.
+func PhaseGraphDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph phases {\n\trankdir=TB;\n")
+	for _, t := range phaseTransitions {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%q];\n", t.From, t.To, t.On)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// PhaseGraphMermaid renders the state machine as a Mermaid state diagram.
+func PhaseGraphMermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    [*] --> %s\n", PhaseLobby)
+	for _, t := range phaseTransitions {
+		fmt.Fprintf(&b, "    %s --> %s: %s\n", t.From, t.To, t.On)
+	}
+	fmt.Fprintf(&b, "    %s --> [*]\n", PhaseGameOver)
+	return b.String()
+}
@@ -57,6 +57,12 @@ func (p *Player) CityColorCount(color DistrictColor) int {
 // HasAllColors returns true if the player's city contains all 5 district colors.
 // Haunted City counts as any color for scoring. School of Magic does NOT.
 func (p *Player) HasAllColors() bool {
+	return p.ColorCount() == 5
+}
+
+// ColorCount returns how many of the 5 district colors the city shows,
+// counting Haunted City as whichever one is missing.
+func (p *Player) ColorCount() int {
 	colors := map[DistrictColor]bool{}
 	wildcards := 0
 	for _, d := range p.City {
@@ -66,22 +72,23 @@ func (p *Player) HasAllColors() bool {
 		}
 		colors[d.Color] = true
 	}
-	missing := 0
+	have := 0
 	for _, c := range []DistrictColor{ColorNoble, ColorReligious, ColorTrade, ColorMilitary, ColorSpecial} {
-		if !colors[c] {
-			missing++
+		if colors[c] {
+			have++
 		}
 	}
-	return missing <= wildcards
+	return min(have+wildcards, 5)
 }
 
-// RemoveFromHand removes the first card with the given name from hand, returns true if found.
-func (p *Player) RemoveFromHand(name string) (District, bool) {
-	for i, d := range p.Hand {
-		if d.Name == name {
-			p.Hand = append(p.Hand[:i], p.Hand[i+1:]...)
-			return d, true
-		}
+// RemoveFromHand removes the card with the given instance ID (and name, if
+// not empty) from hand, returns true if found.
+func (p *Player) RemoveFromHand(id int, name string) (District, bool) {
+	i := IndexOfCard(p.Hand, id, name)
+	if i < 0 {
+		return District{}, false
 	}
-	return District{}, false
+	d := p.Hand[i]
+	p.Hand = append(p.Hand[:i], p.Hand[i+1:]...)
+	return d, true
 }
@@ -22,10 +22,10 @@ func (g *Game) CallCharacter(role CharacterRole) []Event {
 	// Find who has this character
 	ownerID := g.FindCharacterOwner(role)
 
-	callData := map[string]interface{}{"role": role.String(), "number": int(role)}
+	callData := CharacterCallData{Role: role.String(), Number: int(role)}
 	if ownerID != "" {
 		if owner := g.GetPlayer(ownerID); owner != nil {
-			callData["player"] = owner.Name
+			callData.Player = owner.Name
 		}
 	}
 	events = append(events, Event{
@@ -46,7 +46,7 @@ func (g *Game) CallCharacter(role CharacterRole) []Event {
 		events = append(events, Event{
 			Type:   EventMurdered,
 			Player: ownerID,
-			Data:   map[string]interface{}{"role": role.String()},
+			Data:   MurderedData{Role: role.String()},
 		})
 		return events
 	}
@@ -63,18 +63,15 @@ func (g *Game) CallCharacter(role CharacterRole) []Event {
 			events = append(events, Event{
 				Type:   EventRobbed,
 				Player: ownerID,
-				Data: map[string]interface{}{
-					"role": role.String(), "stolen": stolen, "thief": thief.Name,
+				Data: RobbedData{
+					Role: role.String(), Stolen: stolen, Thief: thief.Name, ThiefID: thief.ID,
 				},
 			})
 		}
 	}
 
-	// Apply passive abilities
-	ability, err := g.Abilities.Get(role)
-	if err == nil && ability.IsPassive() {
-		abilityEvents, _ := ability.Apply(g, ownerID, Action{Type: ActionAbility})
-		events = append(events, abilityEvents...)
+	if h, ok := g.ability(role).(CallHook); ok {
+		events = append(events, h.OnCall(g, ownerID)...)
 	}
 
 	// Set up player turn
@@ -86,15 +83,12 @@ func (g *Game) CallCharacter(role CharacterRole) []Event {
 	owner.UsedLab = false
 	owner.UsedSmithy = false
 	owner.CollectedGold = false
-	g.Phase = PhasePlayerTurn
+	phaseEvent := g.setPhase(PhasePlayerTurn)
 
-	events = append(events, Event{
-		Type:   EventPhaseChange,
-		Player: ownerID,
-		Data:   map[string]interface{}{"phase": PhasePlayerTurn.String(), "role": role.String()},
-	})
-
-	return events
+	if h, ok := g.ability(role).(TurnStartHook); ok {
+		events = append(events, h.OnTurnStart(g, ownerID)...)
+	}
+	return append(events, phaseEvent)
 }
 
 // FindCharacterOwner returns the player ID who has the given character, or "".
@@ -1,5 +1,7 @@
 package engine
 
+import "sort"
+
 // ScoreEntry holds scoring breakdown for one player.
 type ScoreEntry struct {
 	PlayerID      string `json:"player_id"`
@@ -10,52 +12,130 @@ type ScoreEntry struct {
 	OtherComplete int    `json:"other_complete"`
 	SpecialBonus  int    `json:"special_bonus"`
 	Total         int    `json:"total"`
+	Colors        int    `json:"colors"` // district colors in the city, 5 earn ColorBonus
+
+	// Tiebreak inputs and the result
+	FinalRole string `json:"final_role,omitempty"` // highest character held in the last round
+	Gold      int    `json:"gold"`
+	Rank      int    `json:"rank"`   // 1 = winner; tied players share a rank
+	Winner    bool   `json:"winner"` // more than one only if the tiebreaks tie too
 }
 
-// CalculateScores computes final scores for all players.
+// CalculateScores computes final scores for all players, best first, with
+// ranks and winners set by RankScores.
 func (g *Game) CalculateScores() []ScoreEntry {
 	entries := make([]ScoreEntry, len(g.Players))
-
 	for i, p := range g.Players {
-		e := ScoreEntry{
-			PlayerID:   p.ID,
-			PlayerName: p.Name,
+		e := g.scoreFor(p, true)
+		for _, role := range p.Characters {
+			if role > e.finalRole() {
+				e.FinalRole = role.String()
+			}
 		}
+		entries[i] = e
+	}
+	RankScores(entries)
+	return entries
+}
 
-		// Sum district costs
-		for _, d := range p.City {
-			e.DistrictScore += d.Cost
-		}
+// ProjectedScores is what every player would score if the game ended now,
+// in seating order and unranked (the tiebreak would reveal characters).
+// Bonuses that depend on hidden information, like the Map Room's hand
+// size, are only counted for viewerID; pass "" for the public projection.
+func (g *Game) ProjectedScores(viewerID string) []ScoreEntry {
+	entries := make([]ScoreEntry, len(g.Players))
+	for i, p := range g.Players {
+		entries[i] = g.scoreFor(p, p.ID == viewerID)
+	}
+	return entries
+}
 
-		// All 5 colors bonus
-		if p.HasAllColors() {
-			e.ColorBonus = 3
-		}
+// scoreFor scores one player's city. private includes the bonuses that
+// depend on information only the player has.
+func (g *Game) scoreFor(p *Player, private bool) ScoreEntry {
+	e := ScoreEntry{
+		PlayerID:   p.ID,
+		PlayerName: p.Name,
+		Gold:       p.Gold,
+		Colors:     p.ColorCount(),
+	}
 
-		// First to complete city
-		if p.ID == g.FirstToComplete {
-			e.FirstComplete = 4
-		} else if len(p.City) >= g.Config.EndCitySize {
-			e.OtherComplete = 2
-		}
+	// Sum district costs
+	for _, d := range p.City {
+		e.DistrictScore += d.Cost
+	}
 
-		// Special district bonuses
-		for _, d := range p.City {
-			switch d.Name {
-			case "University":
-				e.SpecialBonus += 2 // worth 8 instead of 6
-			case "Dragon Gate":
-				e.SpecialBonus += 2 // worth 8 instead of 6
-			case "Imperial Treasury":
-				e.SpecialBonus += p.Gold // 1 point per gold
-			case "Map Room":
+	// All 5 colors bonus
+	if e.Colors == 5 {
+		e.ColorBonus = 3
+	}
+
+	// First to complete city
+	if p.ID == g.FirstToComplete {
+		e.FirstComplete = 4
+	} else if len(p.City) >= g.Config.EndCitySize {
+		e.OtherComplete = 2
+	}
+
+	// Special district bonuses
+	for _, d := range p.City {
+		switch d.Name {
+		case "University":
+			e.SpecialBonus += 2 // worth 8 instead of 6
+		case "Dragon Gate":
+			e.SpecialBonus += 2 // worth 8 instead of 6
+		case "Imperial Treasury":
+			e.SpecialBonus += p.Gold // 1 point per gold
+		case "Map Room":
+			if private {
 				e.SpecialBonus += len(p.Hand) // 1 point per card in hand
 			}
 		}
+	}
 
-		e.Total = e.DistrictScore + e.ColorBonus + e.FirstComplete + e.OtherComplete + e.SpecialBonus
-		entries[i] = e
+	e.Total = e.DistrictScore + e.ColorBonus + e.FirstComplete + e.OtherComplete + e.SpecialBonus
+	return e
+}
+
+// RankScores sorts entries best first and sets Rank and Winner. Ties on
+// Total go to the player who revealed the highest-ranked character in the
+// final round (the official rule), then to the one with more gold left (a
+// house rule). Players still tied after that share the rank.
+func RankScores(entries []ScoreEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].beats(entries[j])
+	})
+	for i := range entries {
+		if i > 0 && !entries[i-1].beats(entries[i]) {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+		entries[i].Winner = entries[i].Rank == 1
+	}
+}
+
+// Winners returns the IDs of the players ranked first.
+func Winners(entries []ScoreEntry) []string {
+	var ids []string
+	for _, e := range entries {
+		if e.Winner {
+			ids = append(ids, e.PlayerID)
+		}
 	}
+	return ids
+}
 
-	return entries
+func (e ScoreEntry) beats(o ScoreEntry) bool {
+	if e.Total != o.Total {
+		return e.Total > o.Total
+	}
+	if a, b := e.finalRole(), o.finalRole(); a != b {
+		return a > b
+	}
+	return e.Gold > o.Gold
+}
+
+func (e ScoreEntry) finalRole() CharacterRole {
+	return ParseRole(e.FinalRole)
 }
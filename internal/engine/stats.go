@@ -0,0 +1,194 @@
+package engine
+
+import "slices"
+
+// PlayerStats counts what one player did, over a round or a whole game.
+type PlayerStats struct {
+	PlayerID      string     `json:"player_id"`
+	PlayerName    string     `json:"player_name"`
+	GoldEarned    int        `json:"gold_earned"` // taken, collected, Merchant bonus, Laboratory
+	GoldStolen    int        `json:"gold_stolen"` // taken from others as the Thief
+	GoldLost      int        `json:"gold_lost"`   // taken by the Thief
+	CardsDrawn    int        `json:"cards_drawn"` // cards from the deck that ended up in hand
+	Built         int        `json:"built"`
+	Destroyed     int        `json:"destroyed"`      // districts this player destroyed
+	DistrictsLost int        `json:"districts_lost"` // districts destroyed in this player's city
+	Murdered      int        `json:"murdered"`
+	Robbed        int        `json:"robbed"`
+	Turns         int        `json:"turns"`
+	Drafted       [][]string `json:"drafted"` // characters picked, one list per round
+}
+
+// RoundSummary is the per-player activity of one round.
+type RoundSummary struct {
+	Round   int           `json:"round"`
+	Players []PlayerStats `json:"players"` // Drafted holds just this round's picks
+}
+
+// Stats aggregates an event stream into per-player totals and per-round
+// summaries. It only reads events, so it works the same on a live game, a
+// replay or a simulation.
+type Stats struct {
+	Players []PlayerStats  `json:"players"`
+	Rounds  []RoundSummary `json:"rounds"`
+
+	round   int
+	current []PlayerStats
+}
+
+// NewStats starts aggregating for the given players.
+func NewStats(players []*Player) *Stats {
+	s := &Stats{}
+	for _, p := range players {
+		s.Players = append(s.Players, PlayerStats{PlayerID: p.ID, PlayerName: p.Name})
+	}
+	return s
+}
+
+// Add counts one event. When the event ends a round it returns that round's
+// summary, which is also kept in Rounds.
+func (s *Stats) Add(ev Event) *RoundSummary {
+	switch d := ev.Data.(type) {
+	case DraftStartData:
+		s.round = d.Round
+		s.current = make([]PlayerStats, len(s.Players))
+		for i, p := range s.Players {
+			s.current[i] = PlayerStats{PlayerID: p.PlayerID, PlayerName: p.PlayerName, Drafted: [][]string{nil}}
+		}
+	case DraftPickData:
+		if i := s.index(ev.Player); i >= 0 {
+			p := &s.Players[i]
+			for len(p.Drafted) < s.round {
+				p.Drafted = append(p.Drafted, nil)
+			}
+			p.Drafted[s.round-1] = append(p.Drafted[s.round-1], d.Character)
+			if s.current != nil {
+				s.current[i].Drafted[0] = append(s.current[i].Drafted[0], d.Character)
+			}
+		}
+	case GoldTakenData:
+		s.count(ev.Player, func(p *PlayerStats) { p.GoldEarned += d.Gold })
+	case GoldCollectedData:
+		s.count(ev.Player, func(p *PlayerStats) { p.GoldEarned += d.Count })
+	case RobbedData:
+		s.count(ev.Player, func(p *PlayerStats) { p.Robbed++; p.GoldLost += d.Stolen })
+		s.count(d.ThiefID, func(p *PlayerStats) { p.GoldStolen += d.Stolen })
+	case MurderedData:
+		s.count(ev.Player, func(p *PlayerStats) { p.Murdered++ })
+	case CardsDrawnData:
+		s.count(ev.Player, func(p *PlayerStats) { p.CardsDrawn += d.Kept })
+	case CardKeptData:
+		s.count(ev.Player, func(p *PlayerStats) { p.CardsDrawn++ })
+	case DistrictBuiltData:
+		s.count(ev.Player, func(p *PlayerStats) { p.Built++ })
+	case AbilityUsedData:
+		s.addAbility(ev.Player, d)
+	case TurnEndData:
+		s.count(ev.Player, func(p *PlayerStats) { p.Turns++ })
+	case RoundEndData:
+		if s.current == nil {
+			return nil
+		}
+		sum := RoundSummary{Round: d.Round, Players: s.current}
+		s.Rounds = append(s.Rounds, sum)
+		s.current = nil
+		return &sum
+	}
+	return nil
+}
+
+func (s *Stats) addAbility(playerID string, d AbilityUsedData) {
+	switch d.Ability {
+	case "merchant":
+		s.count(playerID, func(p *PlayerStats) { p.GoldEarned += d.BonusGold })
+	case "laboratory":
+		s.count(playerID, func(p *PlayerStats) { p.GoldEarned += d.Gold })
+	case "architect":
+		s.count(playerID, func(p *PlayerStats) { p.CardsDrawn += d.ExtraCards })
+	case "smithy":
+		s.count(playerID, func(p *PlayerStats) { p.CardsDrawn += d.CardsDrawn })
+	case "magician":
+		if d.Mode == "discard_draw" {
+			s.count(playerID, func(p *PlayerStats) { p.CardsDrawn += d.Count })
+		}
+	case "warlord":
+		s.count(playerID, func(p *PlayerStats) { p.Destroyed++ })
+		s.count(d.TargetID, func(p *PlayerStats) { p.DistrictsLost++ })
+	}
+}
+
+// count applies f to the player's totals and to the current round.
+func (s *Stats) count(playerID string, f func(p *PlayerStats)) {
+	i := s.index(playerID)
+	if i < 0 {
+		return
+	}
+	f(&s.Players[i])
+	if s.current != nil {
+		f(&s.current[i])
+	}
+}
+
+func (s *Stats) index(playerID string) int {
+	for i, p := range s.Players {
+		if p.PlayerID == playerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Clone returns a deep copy.
+func (s *Stats) Clone() *Stats {
+	c := *s
+	c.Players = clonePlayerStats(s.Players)
+	c.current = clonePlayerStats(s.current)
+	c.Rounds = make([]RoundSummary, len(s.Rounds))
+	for i, r := range s.Rounds {
+		c.Rounds[i] = RoundSummary{Round: r.Round, Players: clonePlayerStats(r.Players)}
+	}
+	return &c
+}
+
+func clonePlayerStats(in []PlayerStats) []PlayerStats {
+	if in == nil {
+		return nil
+	}
+	out := slices.Clone(in)
+	for i := range out {
+		out[i].Drafted = make([][]string, len(in[i].Drafted))
+		for j, picks := range in[i].Drafted {
+			out[i].Drafted[j] = slices.Clone(picks)
+		}
+	}
+	return out
+}
+
+// record feeds events to the game's statistics in order, attaching the round
+// summary to each round_end and the final statistics to game_over.
+func (g *Game) record(events []Event) []Event {
+	if g.stats == nil {
+		return events
+	}
+	for i, ev := range events {
+		sum := g.stats.Add(ev)
+		switch d := ev.Data.(type) {
+		case RoundEndData:
+			d.Summary = sum
+			events[i].Data = d
+		case GameOverData:
+			d.Stats = g.stats.Clone()
+			events[i].Data = d
+		}
+	}
+	return events
+}
+
+// Stats returns a copy of the game's statistics so far, or nil before
+// StartGame.
+func (g *Game) Stats() *Stats {
+	if g.stats == nil {
+		return nil
+	}
+	return g.stats.Clone()
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrUndoNotYours  = errors.New("only the player who made the last action can undo it")
+	ErrUndoRevealed  = errors.New("the last action revealed hidden information and can't be undone")
+)
+
+// CanUndo reports whether playerID may take back the last action: it must be
+// theirs, and it must not have shown anyone something they couldn't see
+// before, such as cards from the deck, the characters left in the draft, or
+// who answers the next character's call.
+func (g *Game) CanUndo(playerID string) error {
+	if len(g.history) == 0 || g.Phase == PhaseGameOver {
+		return ErrNothingToUndo
+	}
+	if g.history[len(g.history)-1].Player != playerID {
+		return ErrUndoNotYours
+	}
+	if g.lastRevealed {
+		return ErrUndoRevealed
+	}
+	return nil
+}
+
+// Undo takes back the last action and returns it. The game is rebuilt by
+// replaying every earlier action from the seed, so the result is exactly the
+// state before the action, random source included.
+func (g *Game) Undo(playerID string) (Move, error) {
+	if err := g.CanUndo(playerID); err != nil {
+		return Move{}, err
+	}
+	last := g.history[len(g.history)-1]
+	r, err := g.replay(g.history[:len(g.history)-1])
+	if err != nil {
+		return Move{}, err
+	}
+	*g = *r
+	return last, nil
+}
+
+// replay plays moves on a new game with g's players, config and seed.
+func (g *Game) replay(moves []Move) (*Game, error) {
+	players := make([]*Player, len(g.joinOrder))
+	for i, id := range g.joinOrder {
+		players[i] = NewPlayer(id, g.GetPlayer(id).Name)
+	}
+	cfg := g.Config
+	cfg.Seed = g.Seed
+	r := NewGame(players, cfg, g.Abilities)
+	r.StartGame()
+	for i, m := range moves {
+		if _, err := r.Apply(m.Player, m.Action); err != nil {
+			return nil, fmt.Errorf("replay move %d (%s %s): %w", i+1, m.Player, m.Action.Type, err)
+		}
+	}
+	return r, nil
+}
+
+// reveals reports whether an action's events showed hidden information.
+func reveals(events []Event) bool {
+	for _, ev := range events {
+		switch ev.Type {
+		case EventCardsDrawn, EventDrawChoice, EventDraftStart, EventDraftPick, EventDraftDone,
+			EventCharacterCall, EventMurdered, EventRobbed:
+			return true
+		case EventAbilityUsed:
+			if d, ok := ev.Data.(AbilityUsedData); ok {
+				switch d.Ability {
+				case "magician", "architect", "smithy", "laboratory":
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ type PlayerInfo struct {
 	ID    string
 	Name  string
 	Ready bool
+	Bot   string // bot difficulty; empty for people
 }
 
 // Lobby represents a game lobby waiting for players.
@@ -20,6 +21,8 @@ type Lobby struct {
 	MaxPlayers int
 	MinPlayers int
 	Started bool
+	Hints   bool // players may ask the advisor for hints
+	Rated   bool // a rated game never allows hints
 }
 
 // NewLobby creates a new lobby.
@@ -53,6 +56,21 @@ func (l *Lobby) Join(id, name string) error {
 	return nil
 }
 
+// AddBot seats a bot. Bots are always ready.
+func (l *Lobby) AddBot(id, name, difficulty string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Started {
+		return fmt.Errorf("game already started")
+	}
+	if len(l.Players) >= l.MaxPlayers {
+		return fmt.Errorf("lobby is full")
+	}
+	l.Players = append(l.Players, &PlayerInfo{ID: id, Name: name, Ready: true, Bot: difficulty})
+	return nil
+}
+
 // Leave removes a player from the lobby.
 func (l *Lobby) Leave(id string) {
 	l.mu.Lock()
@@ -79,6 +97,26 @@ func (l *Lobby) SetReady(id string, ready bool) {
 	}
 }
 
+// SetSettings changes the lobby's settings. A rated game turns hints off.
+func (l *Lobby) SetSettings(hints, rated bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Started {
+		return fmt.Errorf("game already started")
+	}
+	l.Hints, l.Rated = hints && !rated, rated
+	return nil
+}
+
+// Settings returns whether hints are allowed and whether the game is rated.
+func (l *Lobby) Settings() (hints, rated bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.Hints, l.Rated
+}
+
 // CanStart returns true if enough players are ready.
 func (l *Lobby) CanStart() bool {
 	l.mu.Lock()
@@ -4,12 +4,12 @@ import "encoding/json"
 
 // Envelope is the standard WebSocket message wrapper.
 type Envelope struct {
-	Type    string          `json:"type"`
+	Type    MsgType         `json:"type"`
 	Payload json.RawMessage `json:"payload,omitempty"`
 }
 
 // NewEnvelope creates an envelope with a JSON-encoded payload.
-func NewEnvelope(typ string, payload interface{}) (Envelope, error) {
+func NewEnvelope(typ MsgType, payload interface{}) (Envelope, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return Envelope{}, err
@@ -18,7 +18,7 @@ func NewEnvelope(typ string, payload interface{}) (Envelope, error) {
 }
 
 // MustEnvelope is like NewEnvelope but panics on error.
-func MustEnvelope(typ string, payload interface{}) Envelope {
+func MustEnvelope(typ MsgType, payload interface{}) Envelope {
 	e, err := NewEnvelope(typ, payload)
 	if err != nil {
 		panic(err)
@@ -1,40 +1,62 @@
 package protocol
 
+import "citadels/internal/engine"
+
+// MsgType identifies the kind of message carried by an Envelope.
+type MsgType string
+
 // Message types: Server → Client
 const (
-	MsgLobbyUpdate     = "lobby_update"
-	MsgGameState       = "game_state"
-	MsgPlayerState     = "player_state"
-	MsgDraftUpdate     = "draft_update"
-	MsgDraftPick       = "draft_pick"
-	MsgYourTurn        = "your_turn"
-	MsgCharacterCalled = "character_called"
-	MsgDistrictBuilt   = "district_built"
-	MsgAbilityPrompt   = "ability_prompt"
-	MsgDrawChoice      = "draw_choice"
-	MsgGameOver        = "game_over"
-	MsgError           = "error"
-	MsgEvent           = "event"
+	MsgWelcome         MsgType = "welcome" // since v2: reply to hello
+	MsgLobbyUpdate     MsgType = "lobby_update"
+	MsgGameState       MsgType = "game_state"
+	MsgPlayerState     MsgType = "player_state"
+	MsgDraftUpdate     MsgType = "draft_update"
+	MsgDraftPick       MsgType = "draft_pick"
+	MsgYourTurn        MsgType = "your_turn"
+	MsgCharacterCalled MsgType = "character_called"
+	MsgDistrictBuilt   MsgType = "district_built"
+	MsgAbilityPrompt   MsgType = "ability_prompt"
+	MsgDrawChoice      MsgType = "draw_choice"
+	MsgGameOver        MsgType = "game_over"
+	MsgError           MsgType = "error"
+	MsgEvent           MsgType = "event"
+	MsgPreviewResult   MsgType = "preview_result" // reply to preview
+	MsgUndoRequested   MsgType = "undo_requested" // a player asks to undo; the table votes
+	MsgUndoResult      MsgType = "undo_result"
+	MsgAutopilotUpdate MsgType = "autopilot_update" // who the autopilot is playing for
+	MsgHints           MsgType = "hints"            // reply to hint
+	MsgPremoves        MsgType = "premoves"         // the pre-moves a player has queued
 )
 
 // Message types: Client → Server
 const (
-	MsgJoin      = "join"
-	MsgLeave     = "leave"
-	MsgReady     = "ready"
-	MsgStartGame = "start_game"
+	MsgHello       MsgType = "hello" // since v2: version and feature negotiation
+	MsgJoin        MsgType = "join"
+	MsgLeave       MsgType = "leave"
+	MsgReady       MsgType = "ready"
+	MsgStartGame   MsgType = "start_game"
+	MsgPreview     MsgType = "preview" // what would an action do; nothing is applied
+	MsgUndoRequest MsgType = "undo_request"
+	MsgUndoVote    MsgType = "undo_vote"
+	MsgAddBot      MsgType = "add_bot"
+	MsgRemoveBot   MsgType = "remove_bot"
+	MsgAutopilot   MsgType = "autopilot" // hand a few turns to the autopilot, or take back control
+	MsgHint        MsgType = "hint"      // ask the advisor what to do
+	MsgSettings    MsgType = "settings"  // lobby settings, before the game starts
+	MsgPremove     MsgType = "premove"   // queue moves to be played when the player's pick or turn comes
 	// In-game actions use the same names as engine ActionType
-	MsgDraftPickAction = "draft_pick"
-	MsgTakeGold        = "take_gold"
-	MsgDrawCards       = "draw_cards"
-	MsgKeepCard        = "keep_card"
-	MsgBuild           = "build"
-	MsgAbility         = "ability"
-	MsgEndTurn         = "end_turn"
-	MsgCollectGold       = "collect_gold"
-	MsgLabDiscard        = "lab_discard"
-	MsgSmithyDraw        = "smithy_draw"
-	MsgGraveyardRespond  = "graveyard_respond"
+	MsgDraftPickAction  MsgType = "draft_pick"
+	MsgTakeGold         MsgType = "take_gold"
+	MsgDrawCards        MsgType = "draw_cards"
+	MsgKeepCard         MsgType = "keep_card"
+	MsgBuild            MsgType = "build"
+	MsgAbility          MsgType = "ability"
+	MsgEndTurn          MsgType = "end_turn"
+	MsgCollectGold      MsgType = "collect_gold"
+	MsgLabDiscard       MsgType = "lab_discard"
+	MsgSmithyDraw       MsgType = "smithy_draw"
+	MsgGraveyardRespond MsgType = "graveyard_respond"
 )
 
 // LobbyUpdate is sent to all clients when lobby state changes.
@@ -42,12 +64,15 @@ type LobbyUpdate struct {
 	GameID  string       `json:"game_id"`
 	Players []LobbyPlayer `json:"players"`
 	Started bool         `json:"started"`
+	Hints   bool         `json:"hints"` // players may ask for hints
+	Rated   bool         `json:"rated"` // rated games never allow hints
 }
 
 type LobbyPlayer struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
 	Ready bool   `json:"ready"`
+	Bot   string `json:"bot,omitempty"` // bot difficulty; empty for people
 }
 
 // JoinMsg is sent by a player to join the game.
@@ -61,7 +86,156 @@ type ReadyMsg struct {
 	Ready bool `json:"ready"`
 }
 
+// AddBotMsg seats a bot in the lobby.
+type AddBotMsg struct {
+	Difficulty string `json:"difficulty"`
+}
+
+// RemoveBotMsg takes a bot's seat away.
+type RemoveBotMsg struct {
+	PlayerID string `json:"player_id"`
+}
+
+// SettingsMsg changes the lobby's settings before the game starts. Hints
+// are turned off in a rated game whatever Hints says.
+type SettingsMsg struct {
+	Hints bool `json:"hints"`
+	Rated bool `json:"rated"`
+}
+
+// AutopilotMsg asks the autopilot to play the sender's next Turns turns.
+// Zero takes back control.
+type AutopilotMsg struct {
+	Turns int `json:"turns"`
+}
+
+// Why the autopilot is playing for someone.
+const (
+	AutopilotTimeout      = "timeout"      // the turn timer ran out; until the player acts again
+	AutopilotDisconnected = "disconnected" // until the player reconnects
+	AutopilotRequested    = "requested"    // for the turns the player asked for
+)
+
+// AutopilotUpdateMsg lists everyone the autopilot is playing for. It is sent
+// whenever the list changes.
+type AutopilotUpdateMsg struct {
+	Players []AutopilotPlayer `json:"players"`
+}
+
+type AutopilotPlayer struct {
+	PlayerID   string `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Reason     string `json:"reason"`          // AutopilotTimeout, AutopilotDisconnected or AutopilotRequested
+	Turns      int    `json:"turns,omitempty"` // turns left, for AutopilotRequested
+}
+
+// PremoveMsg replaces the sender's pre-moves. Draft ranks characters by
+// preference: whenever the sender picks before the draft ends, the first one
+// still available is picked for them. Pass takes gold and ends their next
+// turn. An empty message clears the queue.
+type PremoveMsg struct {
+	Draft []string `json:"draft,omitempty"`
+	Pass  bool     `json:"pass,omitempty"`
+}
+
+// PremovesMsg tells a player's phones what they have queued. It is sent
+// whenever the queue changes, including when a pre-move is played.
+type PremovesMsg struct {
+	Draft []string `json:"draft"`
+	Pass  bool     `json:"pass"`
+}
+
 // ErrorMsg is sent to a client on error.
 type ErrorMsg struct {
 	Message string `json:"message"`
 }
+
+// YourTurnMsg is sent only to the player who must act now. Actions lists the
+// action types they may send; the other fields narrow down the choices.
+type YourTurnMsg struct {
+	Phase      string            `json:"phase"`
+	Role       string            `json:"role,omitempty"`
+	Actions    []string          `json:"actions"`
+	Characters []string          `json:"characters,omitempty"` // draft_pick choices
+	Buildable  []engine.District `json:"buildable,omitempty"`  // build choices
+	Deadline   int64             `json:"deadline,omitempty"`   // Unix milliseconds
+}
+
+// AbilityPromptMsg asks a player to choose a target for an ability, or to
+// answer an out-of-turn choice such as the Graveyard.
+type AbilityPromptMsg struct {
+	Ability  string           `json:"ability"`
+	Role     string           `json:"role,omitempty"`
+	Options  []string         `json:"options"`
+	Targets  []engine.Target  `json:"targets,omitempty"` // structured form of Options for character abilities
+	District *engine.District `json:"district,omitempty"` // card the choice is about
+	Deadline int64            `json:"deadline,omitempty"`
+}
+
+// DrawChoiceMsg asks the current player which drawn card(s) to keep.
+type DrawChoiceMsg struct {
+	Cards    []engine.District `json:"cards"`
+	Keep     int               `json:"keep"`
+	Deadline int64             `json:"deadline,omitempty"`
+}
+
+// CharacterCalledMsg announces a character call to every client.
+type CharacterCalledMsg struct {
+	Role     string `json:"role"`
+	Number   int    `json:"number"`
+	Player   string `json:"player,omitempty"` // display name, empty if nobody has the role
+	Murdered bool   `json:"murdered,omitempty"`
+}
+
+// PreviewResultMsg answers a preview with what the action would do. Error is
+// set instead if the action is not legal right now.
+type PreviewResultMsg struct {
+	Action engine.Action  `json:"action"`
+	Events []engine.Event `json:"events,omitempty"`
+	Gold   int            `json:"gold"` // the player's gold afterwards
+	Error  string         `json:"error,omitempty"`
+}
+
+// HintsMsg answers a hint request with the sender's legal actions, best
+// first. Reason is a short key such as "build_best"; Params fills in the
+// names and numbers it refers to.
+type HintsMsg struct {
+	Hints []Hint `json:"hints"`
+}
+
+type Hint struct {
+	Action engine.Action     `json:"action"`
+	Reason string            `json:"reason"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// UndoRequestedMsg asks the table to approve taking back a player's last
+// action. Every player in Voters must approve.
+type UndoRequestedMsg struct {
+	PlayerID   string        `json:"player_id"`
+	PlayerName string        `json:"player_name"`
+	Action     engine.Action `json:"action"`
+	Voters     []string      `json:"voters"` // player IDs whose approval is needed
+}
+
+// UndoVoteMsg is a voter's answer to an undo request.
+type UndoVoteMsg struct {
+	Approve bool `json:"approve"`
+}
+
+// UndoResultMsg closes an undo request. If Undone, a corrected game_state
+// and player_state follow.
+type UndoResultMsg struct {
+	PlayerID string        `json:"player_id"`
+	Action   engine.Action `json:"action"`
+	Undone   bool          `json:"undone"`
+	Reason   string        `json:"reason,omitempty"` // "rejected", "cancelled", "expired" or an engine error
+	By       string        `json:"by,omitempty"`     // display name of whoever rejected it
+}
+
+// GameOverMsg carries the final scores to every client.
+type GameOverMsg struct {
+	Scores  []engine.ScoreEntry `json:"scores"`  // ranked, best first
+	Winners []string            `json:"winners"` // player IDs
+	Stats   *engine.Stats       `json:"stats,omitempty"`
+}
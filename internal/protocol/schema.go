@@ -0,0 +1,119 @@
+package protocol
+
+//go:generate go test -run TestEventSchemaUpToDate -update
+
+import (
+	"citadels/internal/engine"
+	"reflect"
+	"strings"
+)
+
+// EventSchema builds a JSON Schema (draft 2020-12) describing the payload of
+// every "event" message, derived from the engine's typed event structs.
+// The committed copy lives in web/static/schema/events.schema.json.
+func EventSchema() map[string]interface{} {
+	defs := map[string]interface{}{}
+	var variants []interface{}
+
+	for _, t := range engine.EventTypes() {
+		data, _ := engine.NewEventData(t)
+		name := reflect.TypeOf(data).Name()
+		defs[name] = schemaFor(reflect.TypeOf(data), defs)
+		variants = append(variants, map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type":   map[string]interface{}{"const": string(t)},
+				"player": map[string]interface{}{"type": "string"},
+				"data":   map[string]interface{}{"$ref": "#/$defs/" + name},
+			},
+			"required":             []string{"type"},
+			"additionalProperties": false,
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Citadels engine event",
+		"version": ProtocolVersion,
+		"oneOf":   variants,
+		"$defs":   defs,
+	}
+}
+
+// schemaFor describes a Go type. Named structs other than the top-level
+// payload are added to defs once and referenced.
+func schemaFor(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), defs)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": refOrInline(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": refOrInline(t.Elem(), defs)}
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, omitempty, skip := jsonField(f)
+			if skip {
+				continue
+			}
+			props[name] = refOrInline(f.Type, defs)
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	return map[string]interface{}{}
+}
+
+func refOrInline(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return schemaFor(t, defs)
+	}
+	if _, ok := defs[t.Name()]; !ok {
+		defs[t.Name()] = map[string]interface{}{} // placeholder guards recursion
+		defs[t.Name()] = schemaFor(t, defs)
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+}
+
+func jsonField(f reflect.StructField) (name string, omitempty, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
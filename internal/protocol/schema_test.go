@@ -0,0 +1,36 @@
+package protocol_test
+
+import (
+	"bytes"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the committed event schema")
+
+const schemaPath = "../../web/static/schema/events.schema.json"
+
+func TestEventSchemaUpToDate(t *testing.T) {
+	got, err := json.MarshalIndent(protocol.EventSchema(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal schema: %v", err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.WriteFile(schemaPath, got, 0o644); err != nil {
+			t.Fatalf("write schema: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(schemaPath)
+	if err != nil {
+		t.Fatalf("read schema: %v (run go generate ./internal/protocol)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("events.schema.json is stale; run go generate ./internal/protocol")
+	}
+}
@@ -0,0 +1,96 @@
+package protocol
+
+import "fmt"
+
+// ProtocolVersion is the protocol version spoken by this server.
+const ProtocolVersion = 2
+
+// MinProtocolVersion is the oldest version the server still accepts.
+// Keep it one behind ProtocolVersion for a deprecation window, so phones
+// with a cached page from the previous deploy can finish their game.
+const MinProtocolVersion = 1
+
+// LegacyProtocolVersion is assumed for clients that never send hello.
+// Version 1 predates the handshake.
+const LegacyProtocolVersion = 1
+
+// Optional features negotiated in the hello handshake.
+const (
+	FeatureEvents    = "events"    // engine events as "event" messages
+	FeaturePrompts   = "prompts"   // your_turn, ability_prompt, draw_choice, character_called, game_over
+	FeatureUndo      = "undo"      // undo_requested, undo_result; the client can vote on undo requests
+	FeatureAutopilot = "autopilot" // autopilot_update
+	FeaturePremoves  = "premoves"  // premoves
+)
+
+// supportedFeatures lists every feature this server can provide.
+var supportedFeatures = []string{
+	FeatureEvents,
+	FeaturePrompts,
+	FeatureUndo,
+	FeatureAutopilot,
+	FeaturePremoves,
+}
+
+// legacyFeatures are the features implied for clients that skip the handshake.
+var legacyFeatures = []string{
+	FeatureEvents,
+}
+
+// HelloMsg is the first message a client sends after connecting.
+type HelloMsg struct {
+	Version  int      `json:"version"`
+	Features []string `json:"features,omitempty"`
+}
+
+// WelcomeMsg is the server's answer to hello with the agreed terms.
+type WelcomeMsg struct {
+	Version    int      `json:"version"`
+	MinVersion int      `json:"min_version"`
+	Features   []string `json:"features"`
+	Deprecated bool     `json:"deprecated,omitempty"` // client should reload to upgrade
+}
+
+// Negotiate picks the protocol version and feature set for a client hello.
+// The server answers with the highest version both sides speak; features are
+// the intersection of what the client asked for and what the server supports.
+func Negotiate(hello HelloMsg) (WelcomeMsg, error) {
+	if hello.Version < MinProtocolVersion {
+		return WelcomeMsg{}, fmt.Errorf("protocol version %d is no longer supported (minimum %d)",
+			hello.Version, MinProtocolVersion)
+	}
+	version := hello.Version
+	if version > ProtocolVersion {
+		version = ProtocolVersion
+	}
+
+	features := []string{}
+	for _, f := range hello.Features {
+		if hasFeature(supportedFeatures, f) && !hasFeature(features, f) {
+			features = append(features, f)
+		}
+	}
+
+	return WelcomeMsg{
+		Version:    version,
+		MinVersion: MinProtocolVersion,
+		Features:   features,
+		Deprecated: version < ProtocolVersion,
+	}, nil
+}
+
+// LegacyFeatures returns the features a pre-handshake client expects.
+func LegacyFeatures() []string {
+	out := make([]string, len(legacyFeatures))
+	copy(out, legacyFeatures)
+	return out
+}
+
+func hasFeature(features []string, f string) bool {
+	for _, have := range features {
+		if have == f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package protocol_test
+
+import (
+	"citadels/internal/protocol"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	w, err := protocol.Negotiate(protocol.HelloMsg{
+		Version:  protocol.ProtocolVersion + 1,
+		Features: []string{protocol.FeatureEvents, "telepathy", protocol.FeatureEvents},
+	})
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if w.Version != protocol.ProtocolVersion {
+		t.Errorf("version: got %d, want %d", w.Version, protocol.ProtocolVersion)
+	}
+	if len(w.Features) != 1 || w.Features[0] != protocol.FeatureEvents {
+		t.Errorf("features: got %v, want [%s]", w.Features, protocol.FeatureEvents)
+	}
+	if w.Deprecated {
+		t.Error("current version should not be deprecated")
+	}
+}
+
+func TestNegotiateDeprecationWindow(t *testing.T) {
+	w, err := protocol.Negotiate(protocol.HelloMsg{Version: protocol.MinProtocolVersion})
+	if err != nil {
+		t.Fatalf("minimum version should be accepted: %v", err)
+	}
+	if protocol.MinProtocolVersion < protocol.ProtocolVersion && !w.Deprecated {
+		t.Error("old version should be flagged deprecated")
+	}
+	if _, err := protocol.Negotiate(protocol.HelloMsg{Version: protocol.MinProtocolVersion - 1}); err == nil {
+		t.Error("version below minimum should be rejected")
+	}
+}
@@ -0,0 +1,228 @@
+// Package record defines the portable game record: a versioned JSON document
+// with everything needed to reproduce a game — the config, the seed, the
+// players, the draft results and every action. Records are exported from
+// live or archived games and validated on import by replaying them through
+// the engine.
+package record
+
+import (
+	"bytes"
+	"citadels/internal/engine"
+	"citadels/internal/engine/abilities"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Format identifies a game record; Version is the current format version.
+// Bump Version whenever a change would make older records replay differently.
+const (
+	Format  = "citadels-record"
+	Version = 1
+)
+
+var (
+	ErrFormat   = errors.New("not a citadels game record")
+	ErrVersion  = errors.New("unsupported record version")
+	ErrMismatch = errors.New("record does not match its replay")
+	ErrGameID   = errors.New("game id may only hold letters, digits and dashes")
+)
+
+// Record is one game, started or finished.
+type Record struct {
+	Format  string        `json:"format"`
+	Version int           `json:"version"`
+	GameID  string        `json:"game_id,omitempty"`
+	Config  Config        `json:"config"`
+	Seed    uint64        `json:"seed"`
+	Players []Player      `json:"players"` // in join order, as passed to engine.NewGame
+	Seating []string      `json:"seating"` // player IDs after the shuffle; the first starts with the crown
+	Drafts  []Draft       `json:"drafts"`  // characters picked, one entry per round
+	Moves   []engine.Move `json:"moves"`
+
+	Scores []engine.ScoreEntry `json:"scores,omitempty"` // final scores, once the game is over
+}
+
+// Config is the part of engine.GameConfig a replay needs.
+type Config struct {
+	EndCitySize int                    `json:"end_city_size"`
+	Districts   []engine.District      `json:"districts"`
+	Roles       []engine.CharacterRole `json:"roles,omitempty"` // omitted for all eight
+}
+
+// Player is a seat at the table.
+type Player struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Draft is what each player picked in one round, by player ID.
+type Draft struct {
+	Round int                 `json:"round"`
+	Picks map[string][]string `json:"picks"`
+}
+
+// FromGame records a game that has been started.
+func FromGame(gameID string, g *engine.Game) Record {
+	r := Record{
+		Format:  Format,
+		Version: Version,
+		GameID:  gameID,
+		Config: Config{
+			EndCitySize: g.Config.EndCitySize,
+			Districts:   g.Config.Districts,
+			Roles:       g.Config.Roles,
+		},
+		Seed:    g.Seed,
+		Seating: []string{},
+		Drafts:  drafts(g.Stats()),
+		Moves:   g.Moves(),
+		Scores:  g.Scores,
+	}
+	for _, id := range g.JoinOrder() {
+		r.Players = append(r.Players, Player{ID: id, Name: g.GetPlayer(id).Name})
+	}
+	for _, p := range g.Players {
+		r.Seating = append(r.Seating, p.ID)
+	}
+	return r
+}
+
+// drafts turns the per-player picks of the statistics into one entry per
+// round.
+func drafts(s *engine.Stats) []Draft {
+	out := []Draft{}
+	if s == nil {
+		return out
+	}
+	for _, p := range s.Players {
+		for i, picks := range p.Drafted {
+			for len(out) <= i {
+				out = append(out, Draft{Round: len(out) + 1, Picks: make(map[string][]string)})
+			}
+			out[i].Picks[p.PlayerID] = picks
+		}
+	}
+	return out
+}
+
+// Parse decodes a record and checks its format and version. Unknown fields
+// are rejected, so a typo can't silently change a replay.
+func Parse(data []byte) (Record, error) {
+	var r Record
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return Record{}, fmt.Errorf("record: %w", err)
+	}
+	if r.Format != Format {
+		return Record{}, ErrFormat
+	}
+	if r.Version < 1 || r.Version > Version {
+		return Record{}, fmt.Errorf("%w: %d", ErrVersion, r.Version)
+	}
+	if r.GameID != "" && !ValidGameID(r.GameID) {
+		return Record{}, fmt.Errorf("%w: %q", ErrGameID, r.GameID)
+	}
+	return r, nil
+}
+
+// ValidGameID reports whether id is a non-empty run of ASCII letters, digits
+// and dashes, which is safe to use as a file name.
+func ValidGameID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Replay plays the record through a new engine and checks that the seating,
+// every move, the drafts and the final scores come out as recorded, and that
+// the engine's invariants hold after each move. It returns the replayed game.
+func (r Record) Replay() (*engine.Game, error) {
+	g, _, err := r.start()
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range r.Moves {
+		if _, err := g.Apply(m.Player, m.Action); err != nil {
+			return nil, fmt.Errorf("record: move %d (%s %s): %w", i+1, m.Player, m.Action.Type, err)
+		}
+		if err := g.CheckInvariants(); err != nil {
+			return nil, fmt.Errorf("record: move %d: %w", i+1, err)
+		}
+	}
+	if err := r.check(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// start creates the game and deals it, returning StartGame's events.
+func (r Record) start() (*engine.Game, []engine.Event, error) {
+	if len(r.Players) == 0 {
+		return nil, nil, fmt.Errorf("record: no players")
+	}
+	if len(r.Config.Districts) == 0 || r.Config.EndCitySize <= 0 {
+		return nil, nil, fmt.Errorf("record: incomplete config")
+	}
+	if r.Seed == 0 {
+		return nil, nil, fmt.Errorf("record: no seed")
+	}
+	players := make([]*engine.Player, len(r.Players))
+	for i, p := range r.Players {
+		players[i] = engine.NewPlayer(p.ID, p.Name)
+	}
+	cfg := engine.GameConfig{
+		Districts:   slices.Clone(r.Config.Districts),
+		EndCitySize: r.Config.EndCitySize,
+		Seed:        r.Seed,
+		Roles:       slices.Clone(r.Config.Roles),
+	}
+	if err := cfg.CheckRoster(len(players)); err != nil {
+		return nil, nil, fmt.Errorf("record: %w", err)
+	}
+	g := engine.NewGame(players, cfg, abilities.NewRegistry())
+	events := g.StartGame()
+
+	var seating []string
+	for _, p := range g.Players {
+		seating = append(seating, p.ID)
+	}
+	if !slices.Equal(seating, r.Seating) {
+		return nil, nil, fmt.Errorf("%w: seating %v, recorded %v", ErrMismatch, seating, r.Seating)
+	}
+	return g, events, nil
+}
+
+// check compares the recorded outcome with the replayed game.
+func (r Record) check(g *engine.Game) error {
+	got := drafts(g.Stats())
+	if len(got) != len(r.Drafts) {
+		return fmt.Errorf("%w: %d drafts, recorded %d", ErrMismatch, len(got), len(r.Drafts))
+	}
+	for i, d := range got {
+		if d.Round != r.Drafts[i].Round || !maps.EqualFunc(d.Picks, r.Drafts[i].Picks, slices.Equal) {
+			return fmt.Errorf("%w: round %d draft", ErrMismatch, d.Round)
+		}
+	}
+
+	if len(g.Scores) != len(r.Scores) {
+		return fmt.Errorf("%w: %d scores, recorded %d", ErrMismatch, len(g.Scores), len(r.Scores))
+	}
+	for i, s := range g.Scores {
+		want := r.Scores[i]
+		if s.PlayerID != want.PlayerID || s.Total != want.Total || s.Rank != want.Rank {
+			return fmt.Errorf("%w: score %d is %s with %d, recorded %s with %d",
+				ErrMismatch, i+1, s.PlayerID, s.Total, want.PlayerID, want.Total)
+		}
+	}
+	return nil
+}
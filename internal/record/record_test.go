@@ -0,0 +1,140 @@
+package record_test
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/engine/abilities"
+	"citadels/internal/record"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// playRandom plays a seeded game to the end with random legal actions.
+func playRandom(t *testing.T, n int, seed uint64) *engine.Game {
+	t.Helper()
+	var players []*engine.Player
+	for i := 0; i < n; i++ {
+		players = append(players, engine.NewPlayer(string(rune('A'+i)), "Player"+string(rune('1'+i))))
+	}
+	cfg := engine.DefaultConfig()
+	cfg.Seed = seed
+	g := engine.NewGame(players, cfg, abilities.NewRegistry())
+	g.StartGame()
+	rng := rand.New(rand.NewPCG(seed, 0))
+	for step := 0; g.Phase != engine.PhaseGameOver; step++ {
+		if step > 5000 {
+			t.Fatalf("game did not finish")
+		}
+		for _, p := range g.Players {
+			if legal := g.LegalActions(p.ID); len(legal) > 0 {
+				if _, err := g.Apply(p.ID, legal[rng.IntN(len(legal))]); err != nil {
+					t.Fatalf("apply: %v", err)
+				}
+				break
+			}
+		}
+	}
+	return g
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	g := playRandom(t, 4, 99)
+	data, err := json.Marshal(record.FromGame("g1", g))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	rec, err := record.Parse(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(rec.Drafts) != g.Round || len(rec.Scores) != 4 {
+		t.Fatalf("record has %d drafts and %d scores", len(rec.Drafts), len(rec.Scores))
+	}
+	replayed, err := rec.Replay()
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.Phase != engine.PhaseGameOver || replayed.Scores[0].Total != g.Scores[0].Total {
+		t.Errorf("replay ended in %s with %+v", replayed.Phase, replayed.Scores)
+	}
+
+	// A record cut short still replays; its scores are simply absent
+	partial := rec
+	partial.Moves = rec.Moves[:10]
+	partial.Drafts = rec.Drafts[:1]
+	partial.Scores = nil
+	if _, err := partial.Replay(); err != nil {
+		t.Errorf("partial replay: %v", err)
+	}
+}
+
+func TestRecordRejected(t *testing.T) {
+	g := playRandom(t, 3, 7)
+	rec := record.FromGame("g1", g)
+
+	tampered := rec
+	tampered.Scores = append([]engine.ScoreEntry(nil), rec.Scores...)
+	tampered.Scores[0].Total++
+	if _, err := tampered.Replay(); !errors.Is(err, record.ErrMismatch) {
+		t.Errorf("tampered score: got %v", err)
+	}
+
+	reseeded := rec
+	reseeded.Seed++
+	if _, err := reseeded.Replay(); err == nil {
+		t.Error("replay with another seed should fail")
+	}
+
+	if _, err := record.Parse([]byte(`{"format":"citadels-record","version":99}`)); !errors.Is(err, record.ErrVersion) {
+		t.Errorf("future version: got %v", err)
+	}
+	if _, err := record.Parse([]byte(`{"format":"chess"}`)); !errors.Is(err, record.ErrFormat) {
+		t.Errorf("wrong format: got %v", err)
+	}
+	if _, err := record.Parse([]byte(`{"format":"citadels-record","version":1,"game_id":"../../etc/x"}`)); !errors.Is(err, record.ErrGameID) {
+		t.Errorf("path in game id: got %v", err)
+	}
+}
+
+func TestRecordFrames(t *testing.T) {
+	g := playRandom(t, 4, 5)
+	rec := record.FromGame("g1", g)
+
+	marks, err := rec.Marks()
+	if err != nil {
+		t.Fatalf("marks: %v", err)
+	}
+	rounds := 0
+	for _, m := range marks {
+		if m.Kind == "round" {
+			rounds++
+		}
+	}
+	if marks[0].Step != 0 || rounds != g.Round {
+		t.Errorf("marks start at step %d with %d rounds, want 0 and %d", marks[0].Step, rounds, g.Round)
+	}
+
+	deal, err := rec.Frame(0, true)
+	if err != nil {
+		t.Fatalf("frame 0: %v", err)
+	}
+	if deal.Move != nil || len(deal.Events) == 0 || len(deal.View.Players[0].Hand) != 4 {
+		t.Errorf("deal frame: move %v, %d events, hand %v", deal.Move, len(deal.Events), deal.View.Players[0].Hand)
+	}
+	hidden, _ := rec.Frame(0, false)
+	if hidden.View.Players[0].Hand != nil {
+		t.Error("public frame shows a hand")
+	}
+
+	last, err := rec.Frame(len(rec.Moves), false)
+	if err != nil {
+		t.Fatalf("last frame: %v", err)
+	}
+	if last.View.Phase != engine.PhaseGameOver.String() || last.Move == nil {
+		t.Errorf("last frame in %s", last.View.Phase)
+	}
+	if _, err := rec.Frame(len(rec.Moves)+1, false); err == nil {
+		t.Error("frame past the end should fail")
+	}
+}
@@ -0,0 +1,80 @@
+package record
+
+import (
+	"citadels/internal/engine"
+	"fmt"
+)
+
+// Frame is the game as it stood after some number of moves of a record.
+type Frame struct {
+	Step   int                   `json:"step"`           // moves applied; 0 is the deal
+	Steps  int                   `json:"steps"`          // moves in the record
+	Move   *engine.Move          `json:"move,omitempty"` // the move that led here
+	Events []engine.Event        `json:"events"`         // what it caused; StartGame's events at step 0
+	View   engine.PublicViewData `json:"view"`
+}
+
+// Mark is a step worth seeking to: the start of a round or of a turn.
+type Mark struct {
+	Step   int    `json:"step"`
+	Round  int    `json:"round"`
+	Kind   string `json:"kind"`             // "round" or "turn"
+	Player string `json:"player,omitempty"` // turn: the player's name
+	Role   string `json:"role,omitempty"`   // turn: the character played
+}
+
+// Frame replays the first step moves and returns the game at that point.
+// With reveal, the view shows every hand and character (RevealedView);
+// otherwise it is what the TV showed (PublicView).
+func (r Record) Frame(step int, reveal bool) (Frame, error) {
+	if step < 0 || step > len(r.Moves) {
+		return Frame{}, fmt.Errorf("record: step %d of %d", step, len(r.Moves))
+	}
+	g, events, err := r.start()
+	if err != nil {
+		return Frame{}, err
+	}
+	f := Frame{Step: step, Steps: len(r.Moves)}
+	for i, m := range r.Moves[:step] {
+		if events, err = g.Apply(m.Player, m.Action); err != nil {
+			return Frame{}, fmt.Errorf("record: move %d (%s %s): %w", i+1, m.Player, m.Action.Type, err)
+		}
+		f.Move = &r.Moves[i]
+	}
+	f.Events = events
+	if reveal {
+		f.View = g.RevealedView()
+	} else {
+		f.View = g.PublicView()
+	}
+	return f, nil
+}
+
+// Marks replays the record once and lists where each round and each turn
+// begins, for seeking.
+func (r Record) Marks() ([]Mark, error) {
+	g, _, err := r.start()
+	if err != nil {
+		return nil, err
+	}
+	marks := []Mark{{Step: 0, Round: g.Round, Kind: "round"}}
+	for i, m := range r.Moves {
+		round, role := g.Round, g.CurrentTurnRole
+		if _, err := g.Apply(m.Player, m.Action); err != nil {
+			return nil, fmt.Errorf("record: move %d (%s %s): %w", i+1, m.Player, m.Action.Type, err)
+		}
+		switch {
+		case g.Round != round:
+			marks = append(marks, Mark{Step: i + 1, Round: g.Round, Kind: "round"})
+		case g.CurrentTurnRole != role && g.CurrentTurnRole != 0:
+			marks = append(marks, Mark{
+				Step:   i + 1,
+				Round:  g.Round,
+				Kind:   "turn",
+				Player: g.GetPlayer(g.CurrentTurnPlayer).Name,
+				Role:   g.CurrentTurnRole.String(),
+			})
+		}
+	}
+	return marks, nil
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"log"
+)
+
+// maxAutopilotTurns caps how many turns a player can hand to the autopilot
+// at once.
+const maxAutopilotTurns = 5
+
+// autopilot plays for a person: after their turn timer runs out, while they
+// are disconnected, or for a few turns they asked for. It is driven like a bot
+// seat (see bots.go) and hands back control as soon as the player acts.
+type autopilot struct {
+	strategy bot.Strategy
+	reason   string // protocol.Autopilot*
+	turns    int    // turns left, for protocol.AutopilotRequested
+}
+
+// engageAutopilot lets the autopilot play for a person. A player it already
+// plays for keeps their strategy; only the reason and turns change.
+func (h *Hub) engageAutopilot(playerID, reason string, turns int) {
+	if h.game == nil || h.game.Phase == engine.PhaseGameOver || h.isBot(playerID) || h.game.GetPlayer(playerID) == nil {
+		return
+	}
+	a := h.autopilots[playerID]
+	if a == nil {
+		s, err := h.newStrategy(h.autopilotLevel, playerID)
+		if err != nil {
+			log.Printf("game %s: autopilot: %v", h.gameID, err)
+			return
+		}
+		a = &autopilot{strategy: s}
+		h.autopilots[playerID] = a
+	}
+	a.reason, a.turns = reason, turns
+	h.broadcastFeature(protocol.FeatureAutopilot, h.autopilotEnvelope())
+	h.scheduleBots()
+}
+
+// releaseAutopilot gives the player back control.
+func (h *Hub) releaseAutopilot(playerID string) {
+	if _, ok := h.autopilots[playerID]; !ok {
+		return
+	}
+	delete(h.autopilots, playerID)
+	h.broadcastFeature(protocol.FeatureAutopilot, h.autopilotEnvelope())
+}
+
+func (h *Hub) handleAutopilot(msg IncomingMessage) {
+	var req protocol.AutopilotMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &req); err != nil {
+		h.sendError(msg.Client, "invalid autopilot message")
+		return
+	}
+	pid := msg.Client.PlayerID
+	if h.game == nil || h.game.GetPlayer(pid) == nil {
+		h.sendError(msg.Client, "you are not playing")
+		return
+	}
+	if req.Turns <= 0 {
+		h.releaseAutopilot(pid)
+		return
+	}
+	h.engageAutopilot(pid, protocol.AutopilotRequested, min(req.Turns, maxAutopilotTurns))
+}
+
+// countAutopilotTurns counts down requested turns as they end.
+func (h *Hub) countAutopilotTurns(events []engine.Event) {
+	for _, ev := range events {
+		if ev.Type != engine.EventTurnEnd {
+			continue
+		}
+		a := h.autopilots[ev.Player]
+		if a == nil || a.reason != protocol.AutopilotRequested {
+			continue
+		}
+		if a.turns--; a.turns <= 0 {
+			h.releaseAutopilot(ev.Player)
+		} else {
+			h.broadcastFeature(protocol.FeatureAutopilot, h.autopilotEnvelope())
+		}
+	}
+}
+
+// handleTimerExpired hands whoever the table is waiting for to the
+// autopilot, which moves for them at once. An undo vote still open by then
+// is closed first: bots don't move during a vote, so a voter who never
+// answers would hold up the table.
+func (h *Hub) handleTimerExpired() {
+	if h.game == nil {
+		return
+	}
+	if h.undo != nil {
+		h.closeUndo(protocol.UndoResultMsg{Reason: "expired"})
+	}
+	for _, p := range h.game.Players {
+		if h.strategyFor(p.ID) == nil && len(h.game.LegalActions(p.ID)) > 0 {
+			h.engageAutopilot(p.ID, protocol.AutopilotTimeout, 0)
+		}
+	}
+	if h.botTimer != nil {
+		h.botTimer.Stop()
+	}
+	if !h.botSearching {
+		h.handleBotMove()
+	}
+}
+
+// clientGone engages the autopilot for a player whose last connection
+// closed during the game.
+func (h *Hub) clientGone(client *Client) {
+	if h.game == nil || client.Type != ClientPlayer || h.connected(client.PlayerID) {
+		return
+	}
+	h.engageAutopilot(client.PlayerID, protocol.AutopilotDisconnected, 0)
+}
+
+// clientBack releases a reconnecting player from the autopilot that stood in
+// while they were away.
+func (h *Hub) clientBack(client *Client) {
+	if a := h.autopilots[client.PlayerID]; a != nil && client.Type == ClientPlayer && a.reason == protocol.AutopilotDisconnected {
+		h.releaseAutopilot(client.PlayerID)
+	}
+}
+
+// connected reports whether a phone is connected for the player.
+func (h *Hub) connected(playerID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		if c.Type == ClientPlayer && c.PlayerID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) autopilotEnvelope() protocol.Envelope {
+	msg := protocol.AutopilotUpdateMsg{Players: []protocol.AutopilotPlayer{}}
+	for _, p := range h.game.Players {
+		if a := h.autopilots[p.ID]; a != nil {
+			msg.Players = append(msg.Players, protocol.AutopilotPlayer{
+				PlayerID:   p.ID,
+				PlayerName: p.Name,
+				Reason:     a.reason,
+				Turns:      a.turns,
+			})
+		}
+	}
+	return protocol.MustEnvelope(protocol.MsgAutopilotUpdate, msg)
+}
+
+// sendAutopilotToClient tells a client that (re)connected who the autopilot
+// is playing for.
+func (h *Hub) sendAutopilotToClient(client *Client) {
+	if h.game != nil && len(h.autopilots) > 0 && client.Has(protocol.FeatureAutopilot) {
+		client.SendEnvelope(h.autopilotEnvelope())
+	}
+}
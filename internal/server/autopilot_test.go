@@ -0,0 +1,108 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"testing"
+)
+
+func TestAutopilotTimeout(t *testing.T) {
+	h := newTestHub(t, 1, "a", "b", "c")
+	picker := h.game.Draft.CurrentPickerID()
+
+	h.handleTimerExpired()
+	a := h.autopilots[picker]
+	if a == nil || a.reason != protocol.AutopilotTimeout {
+		t.Fatalf("timed-out picker %s not on autopilot: %+v", picker, a)
+	}
+	if len(h.game.Draft.Picks[picker]) != 1 {
+		t.Errorf("the autopilot didn't pick for %s at once", picker)
+	}
+	for id := range h.autopilots {
+		if id != picker {
+			t.Errorf("%s had nothing to do but is on autopilot", id)
+		}
+	}
+}
+
+func TestAutopilotRequestedTurns(t *testing.T) {
+	h := newTestHub(t, 2, "a", "b")
+	tv := &Client{hub: h, send: make(chan []byte, 256), Type: ClientTV}
+	tv.setProtocol(protocol.ProtocolVersion, []string{protocol.FeatureAutopilot})
+	h.clients[tv] = true
+	c := connect(h, "a")
+
+	post(h, c, protocol.MsgAutopilot, protocol.AutopilotMsg{Turns: 99})
+	if a := h.autopilots["a"]; a == nil || a.reason != protocol.AutopilotRequested || a.turns != maxAutopilotTurns {
+		t.Fatalf("requested autopilot: %+v", a)
+	}
+	if len(received(tv, protocol.MsgAutopilotUpdate)) == 0 {
+		t.Error("the TV wasn't told")
+	}
+
+	turnEnd := []engine.Event{{Type: engine.EventTurnEnd, Player: "a"}}
+	for i := maxAutopilotTurns - 1; i > 0; i-- {
+		h.countAutopilotTurns(turnEnd)
+		if a := h.autopilots["a"]; a == nil || a.turns != i {
+			t.Fatalf("after a turn: %+v, want %d turns left", a, i)
+		}
+	}
+	h.countAutopilotTurns(turnEnd)
+	if h.autopilots["a"] != nil {
+		t.Error("the autopilot stayed after the last requested turn")
+	}
+	env := received(tv, protocol.MsgAutopilotUpdate)
+	var last protocol.AutopilotUpdateMsg
+	json.Unmarshal(env[len(env)-1].Payload, &last)
+	if len(last.Players) != 0 {
+		t.Errorf("last update still lists %+v", last.Players)
+	}
+
+	post(h, c, protocol.MsgAutopilot, protocol.AutopilotMsg{Turns: 2})
+	post(h, c, protocol.MsgAutopilot, protocol.AutopilotMsg{Turns: 0})
+	if h.autopilots["a"] != nil {
+		t.Error("turns: 0 didn't take back control")
+	}
+}
+
+func TestAutopilotDisconnect(t *testing.T) {
+	h := newTestHub(t, 3, "a", "b")
+	c := connect(h, "a")
+
+	delete(h.clients, c)
+	h.clientGone(c)
+	if a := h.autopilots["a"]; a == nil || a.reason != protocol.AutopilotDisconnected {
+		t.Fatalf("disconnected player not on autopilot: %+v", a)
+	}
+	back := connect(h, "a")
+	h.clientBack(back)
+	if h.autopilots["a"] != nil {
+		t.Error("reconnecting didn't take back control")
+	}
+
+	// Only the disconnection's autopilot ends on reconnect
+	post(h, back, protocol.MsgAutopilot, protocol.AutopilotMsg{Turns: 2})
+	h.clientBack(connect(h, "a"))
+	if h.autopilots["a"] == nil {
+		t.Error("a reconnect released the autopilot the player asked for")
+	}
+}
+
+func TestAutopilotReleasedByAction(t *testing.T) {
+	h := newTestHub(t, 4, "a", "b")
+	picker := h.game.Draft.CurrentPickerID()
+	c := connect(h, picker)
+	post(h, c, protocol.MsgAutopilot, protocol.AutopilotMsg{Turns: 2})
+
+	// A refused action leaves the autopilot in place
+	post(h, c, protocol.MsgEndTurn, struct{}{})
+	if h.autopilots[picker] == nil {
+		t.Fatal("a refused action released the autopilot")
+	}
+	legal := h.game.LegalActions(picker)
+	post(h, c, protocol.MsgDraftPickAction, map[string]any{"character": legal[0].Character})
+	if h.autopilots[picker] != nil {
+		t.Error("acting didn't take back control")
+	}
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// botThinkDelay is how long a bot waits before each move, so the table can
+// follow what it does. A searching bot's search counts towards it.
+const botThinkDelay = 1200 * time.Millisecond
+
+// botMove is a searching bot's decision, posted back to Run's goroutine.
+// Seq is the state it was made for; a stale decision is dropped.
+type botMove struct {
+	Player string        `json:"player"`
+	Seq    int           `json:"seq"`
+	Action engine.Action `json:"action"`
+}
+
+func (h *Hub) handleAddBot(msg IncomingMessage) {
+	var req protocol.AddBotMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &req); err != nil {
+		h.sendError(msg.Client, "invalid add_bot message")
+		return
+	}
+	if !slices.Contains(bot.Difficulties, bot.Difficulty(req.Difficulty)) {
+		h.sendError(msg.Client, bot.ErrDifficulty.Error())
+		return
+	}
+	if err := h.lobby.AddBot("bot-"+GeneratePlayerID(), h.botName(), req.Difficulty); err != nil {
+		h.sendError(msg.Client, err.Error())
+		return
+	}
+	h.sendLobbyUpdate()
+}
+
+func (h *Hub) handleRemoveBot(msg IncomingMessage) {
+	var req protocol.RemoveBotMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &req); err != nil {
+		h.sendError(msg.Client, "invalid remove_bot message")
+		return
+	}
+	if h.lobby.Started {
+		return
+	}
+	for _, p := range h.lobby.GetPlayers() {
+		if p.ID == req.PlayerID && p.Bot != "" {
+			h.lobby.Leave(p.ID)
+			h.sendLobbyUpdate()
+			return
+		}
+	}
+	h.sendError(msg.Client, "no such bot")
+}
+
+// botName returns the first bot name nobody in the lobby uses.
+func (h *Hub) botName() string {
+	taken := map[string]bool{}
+	for _, p := range h.lobby.GetPlayers() {
+		taken[p.Name] = true
+	}
+	for _, name := range bot.Names {
+		if !taken[name] {
+			return name
+		}
+	}
+	return fmt.Sprintf("Bot %d", len(taken)+1)
+}
+
+// startBots creates a strategy for every bot seat when the game starts.
+func (h *Hub) startBots() {
+	h.bots = make(map[string]bot.Strategy)
+	for _, p := range h.lobby.GetPlayers() {
+		if p.Bot == "" {
+			continue
+		}
+		s, err := h.newStrategy(bot.Difficulty(p.Bot), p.ID)
+		if err != nil {
+			log.Printf("game %s: bot %s: %v", h.gameID, p.Name, err)
+			continue
+		}
+		h.bots[p.ID] = s
+	}
+}
+
+// newStrategy creates a bot strategy with the hub's search budget.
+func (h *Hub) newStrategy(d bot.Difficulty, playerID string) (bot.Strategy, error) {
+	s, err := bot.New(d, playerID, rand.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := s.(*bot.ISMCTS); ok && h.botBudget > 0 {
+		m.Budget = h.botBudget
+	}
+	return s, nil
+}
+
+func (h *Hub) isBot(playerID string) bool {
+	_, ok := h.bots[playerID]
+	return ok
+}
+
+// strategyFor returns the strategy that plays for the player: their bot,
+// the pre-moves they queued for this decision, or the autopilot standing in
+// for them. It returns nil for a person at the controls.
+func (h *Hub) strategyFor(playerID string) bot.Strategy {
+	if s, ok := h.bots[playerID]; ok {
+		return s
+	}
+	if p := h.premovesFor(playerID); p != nil {
+		return p
+	}
+	if a, ok := h.autopilots[playerID]; ok {
+		return a.strategy
+	}
+	return nil
+}
+
+// scheduleBots arms the think timer if a bot, or the autopilot, has something
+// to do. The timer
+// posts a bot_move message, so the move itself runs in Run's goroutine.
+func (h *Hub) scheduleBots() {
+	if h.game == nil || h.botTimer != nil || h.botSearching || h.undo != nil {
+		return
+	}
+	pid := h.nextBot()
+	if pid == "" {
+		return
+	}
+	delay := botThinkDelay
+	if s, ok := h.strategyFor(pid).(*bot.ISMCTS); ok {
+		delay = max(0, delay-s.Budget)
+	}
+	h.botTimer = time.AfterFunc(delay, func() {
+		h.internal <- protocol.Envelope{Type: "bot_move"}
+	})
+}
+
+// nextBot returns the first player with a strategy and a legal action, in
+// seating order.
+func (h *Hub) nextBot() string {
+	for _, p := range h.game.Players {
+		if h.strategyFor(p.ID) != nil && len(h.game.LegalActions(p.ID)) > 0 {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// handleBotMove lets one bot act. The game may have moved on since the timer
+// was armed, so the bot to move is looked up again. A Searcher searches a
+// determinized copy in its own goroutine and posts a bot_action back.
+func (h *Hub) handleBotMove() {
+	h.botTimer = nil
+	if h.game == nil || h.undo != nil {
+		return
+	}
+	pid := h.nextBot()
+	if pid == "" {
+		return
+	}
+	s := h.strategyFor(pid)
+	if s, ok := s.(bot.Searcher); ok {
+		h.botSearching = true
+		snapshot, seq := h.game.Determinize(pid, rand.Uint64()), h.stateSeq
+		go func() {
+			payload, _ := json.Marshal(botMove{Player: pid, Seq: seq, Action: s.Search(snapshot)})
+			h.internal <- protocol.Envelope{Type: "bot_action", Payload: payload}
+		}()
+		return
+	}
+	h.applyBotAction(pid, s.Act(h.game.ViewFor(pid), h.game.LegalActions(pid)))
+}
+
+// handleBotAction applies a searching bot's decision if the game is still
+// where the search started and nobody took over from the autopilot.
+func (h *Hub) handleBotAction(env protocol.Envelope) {
+	h.botSearching = false
+	var m botMove
+	if err := json.Unmarshal(env.Payload, &m); err != nil || h.game == nil {
+		return
+	}
+	if m.Seq != h.stateSeq || h.undo != nil || h.strategyFor(m.Player) == nil {
+		h.scheduleBots()
+		return
+	}
+	h.applyBotAction(m.Player, m.Action)
+}
+
+// applyBotAction applies a bot's action. Should the engine refuse it, the
+// bot plays its first legal action instead, so the game never stalls on a bot.
+func (h *Hub) applyBotAction(pid string, action engine.Action) {
+	events, err := h.game.Apply(pid, action)
+	if err != nil {
+		log.Printf("game %s: bot %s chose %s: %v", h.gameID, pid, action.Type, err)
+		legal := h.game.LegalActions(pid)
+		if len(legal) == 0 {
+			return
+		}
+		if events, err = h.game.Apply(pid, legal[0]); err != nil {
+			log.Printf("game %s: bot %s: %v", h.gameID, pid, err)
+			return
+		}
+	}
+	h.broadcastEvents(events)
+	h.broadcastState()
+}
@@ -31,16 +31,37 @@ type Client struct {
 	send     chan []byte
 	PlayerID string
 	Type     ClientType
+
+	// Negotiated protocol terms. Clients start at the legacy version
+	// and are upgraded when they send hello.
+	Version  int
+	Features map[string]bool
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, playerID string, clientType ClientType) *Client {
-	return &Client{
+	c := &Client{
 		hub:      hub,
 		conn:     conn,
 		send:     make(chan []byte, 256),
 		PlayerID: playerID,
 		Type:     clientType,
 	}
+	c.setProtocol(protocol.LegacyProtocolVersion, protocol.LegacyFeatures())
+	return c
+}
+
+// setProtocol records the protocol version and features agreed with the client.
+func (c *Client) setProtocol(version int, features []string) {
+	c.Version = version
+	c.Features = make(map[string]bool, len(features))
+	for _, f := range features {
+		c.Features[f] = true
+	}
+}
+
+// Has reports whether the client negotiated the given feature.
+func (c *Client) Has(feature string) bool {
+	return c.Features[feature]
 }
 
 // ReadPump reads messages from the WebSocket and forwards to the hub.
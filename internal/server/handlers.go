@@ -1,12 +1,20 @@
 package server
 
 import (
+	"citadels/internal/archive"
+	"citadels/internal/bot"
+	"citadels/internal/engine"
 	"citadels/internal/lobby"
 	qr "citadels/internal/qrcode"
+	"citadels/internal/record"
+	"citadels/internal/summary"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -19,13 +27,18 @@ var upgrader = websocket.Upgrader{
 type Handlers struct {
 	LobbyMgr *lobby.Manager
 	Hubs     map[string]*Hub
+	Archive  *archive.Archive
 	Port     int
+
+	BotBudget time.Duration  // search time per decision for hard bots; 0 keeps the default
+	Autopilot bot.Difficulty // strategy that plays for absent players; empty keeps normal
 }
 
-func NewHandlers(port int) *Handlers {
+func NewHandlers(port int, arch *archive.Archive) *Handlers {
 	return &Handlers{
 		LobbyMgr: lobby.NewManager(),
 		Hubs:     make(map[string]*Hub),
+		Archive:  arch,
 		Port:     port,
 	}
 }
@@ -34,7 +47,11 @@ func NewHandlers(port int) *Handlers {
 func (h *Handlers) HandleCreateGame(w http.ResponseWriter, r *http.Request) {
 	gameID := h.LobbyMgr.Create()
 	lob := h.LobbyMgr.Get(gameID)
-	hub := NewHub(gameID, lob)
+	hub := NewHub(gameID, lob, h.Archive)
+	hub.botBudget = h.BotBudget
+	if h.Autopilot != "" {
+		hub.autopilotLevel = h.Autopilot
+	}
 	h.Hubs[gameID] = hub
 	go hub.Run()
 
@@ -103,6 +120,180 @@ func (h *Handlers) HandleListGames(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(games)
 }
 
+// HandleResult returns the archived result of a finished game.
+func (h *Handlers) HandleResult(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.Archive.Get(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "no result for this game", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// HandleSummaryPNG renders the shareable summary card of a finished game.
+func (h *Handlers) HandleSummaryPNG(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.Archive.Get(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "no result for this game", http.StatusNotFound)
+		return
+	}
+	png, err := summary.Render(res)
+	if err != nil {
+		http.Error(w, "summary rendering failed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400") // results never change
+	w.Write(png)
+}
+
+// recordFor returns the record of a started game, live or archived.
+func (h *Handlers) recordFor(id string) (record.Record, bool) {
+	if hub, ok := h.Hubs[id]; ok {
+		return hub.Record()
+	}
+	if res, ok := h.Archive.Get(id); ok && res.Record != nil {
+		return *res.Record, true
+	}
+	return record.Record{}, false
+}
+
+// HandleRecord exports the game record of a finished game. A record holds
+// the seed and every draft, so it is refused while the game is on.
+func (h *Handlers) HandleRecord(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rec, ok := h.finishedRecord(w, id)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="citadels-%s.json"`, id))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(rec)
+}
+
+// ReplayIndex describes a finished game for the replay viewer.
+type ReplayIndex struct {
+	GameID string        `json:"game_id"`
+	Steps  int           `json:"steps"`
+	Marks  []record.Mark `json:"marks"`
+}
+
+// finishedRecord is recordFor restricted to finished games: a record or a
+// replay shows hidden information, so it must not be available while the
+// game is on.
+func (h *Handlers) finishedRecord(w http.ResponseWriter, id string) (record.Record, bool) {
+	rec, ok := h.recordFor(id)
+	if !ok {
+		http.Error(w, "no record for this game", http.StatusNotFound)
+		return record.Record{}, false
+	}
+	if len(rec.Scores) == 0 {
+		http.Error(w, "game still in progress", http.StatusForbidden)
+		return record.Record{}, false
+	}
+	return rec, true
+}
+
+// HandleReplay lists the steps of a finished game and where each round and
+// turn begins.
+func (h *Handlers) HandleReplay(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rec, ok := h.finishedRecord(w, id)
+	if !ok {
+		return
+	}
+	marks, err := rec.Marks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReplayIndex{GameID: id, Steps: len(rec.Moves), Marks: marks})
+}
+
+// HandleReplayFrame rebuilds a finished game after the given number of
+// moves. With reveal=1 every hand and character is shown.
+func (h *Handlers) HandleReplayFrame(w http.ResponseWriter, r *http.Request) {
+	rec, ok := h.finishedRecord(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	step, err := strconv.Atoi(r.PathValue("step"))
+	if err != nil {
+		http.Error(w, "invalid step", http.StatusBadRequest)
+		return
+	}
+	frame, err := rec.Frame(step, r.URL.Query().Get("reveal") == "1")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(frame)
+}
+
+// ImportResult reports a record that replayed successfully.
+type ImportResult struct {
+	GameID   string `json:"game_id"`
+	Moves    int    `json:"moves"`
+	Round    int    `json:"round"`
+	Phase    string `json:"phase"`
+	Finished bool   `json:"finished"` // finished games are added to the archive
+}
+
+// HandleImportRecord validates an uploaded game record by replaying it.
+// Finished games are archived under a new ID, marked imported.
+func (h *Handlers) HandleImportRecord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST a game record", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 4<<20))
+	if err != nil {
+		http.Error(w, "record too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	rec, err := record.Parse(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	g, err := rec.Replay()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	id := "import-" + GeneratePlayerID()[:8]
+	res := ImportResult{
+		GameID:   id,
+		Moves:    len(rec.Moves),
+		Round:    g.Round,
+		Phase:    g.Phase.String(),
+		Finished: g.Phase == engine.PhaseGameOver,
+	}
+	if res.Finished {
+		result := archive.FromGame(id, g)
+		result.Imported = true
+		if err := h.Archive.Save(result); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// HandleListResults returns the results of games played here, newest
+// first. Imported games are only reachable by ID.
+func (h *Handlers) HandleListResults(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Archive.Played())
+}
+
 // HandlePlayerID returns a new player ID.
 func (h *Handlers) HandlePlayerID(w http.ResponseWriter, r *http.Request) {
 	id := GeneratePlayerID()
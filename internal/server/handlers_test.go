@@ -0,0 +1,43 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"citadels/internal/record"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleRecordLiveGame(t *testing.T) {
+	hub := newTestHub(t, 13, "a", "b")
+	h := NewHandlers(0, hub.archive)
+	h.Hubs["test"] = hub
+
+	get := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/games/test/record", nil)
+		r.SetPathValue("id", "test")
+		w := httptest.NewRecorder()
+		h.HandleRecord(w, r)
+		return w
+	}
+	if w := get(); w.Code != http.StatusForbidden {
+		t.Fatalf("live game's record: status %d, want 403", w.Code)
+	}
+
+	for _, p := range hub.game.Players {
+		hub.engageAutopilot(p.ID, protocol.AutopilotDisconnected, 0)
+	}
+	for step := 0; hub.game.Phase != engine.PhaseGameOver && step < 3000; step++ {
+		hub.handleBotMove()
+	}
+	w := get()
+	if w.Code != http.StatusOK {
+		t.Fatalf("finished game's record: status %d", w.Code)
+	}
+	var rec record.Record
+	if err := json.Unmarshal(w.Body.Bytes(), &rec); err != nil || len(rec.Scores) == 0 {
+		t.Errorf("finished game's record: %v, %d scores", err, len(rec.Scores))
+	}
+}
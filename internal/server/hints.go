@@ -0,0 +1,52 @@
+package server
+
+import (
+	"citadels/internal/bot"
+	"citadels/internal/protocol"
+	"encoding/json"
+)
+
+// maxHints is how many suggestions a hint request gets.
+const maxHints = 3
+
+// handleSettings changes the lobby's settings before the game starts.
+func (h *Hub) handleSettings(msg IncomingMessage) {
+	var req protocol.SettingsMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &req); err != nil {
+		h.sendError(msg.Client, "invalid settings message")
+		return
+	}
+	if err := h.lobby.SetSettings(req.Hints, req.Rated); err != nil {
+		h.sendError(msg.Client, err.Error())
+		return
+	}
+	h.sendLobbyUpdate()
+}
+
+// handleHint ranks the sender's legal actions the way a normal bot would
+// and sends back the best few, with reasons. Only the sender's own view is
+// used, so a hint gives nothing away.
+func (h *Hub) handleHint(msg IncomingMessage) {
+	pid := msg.Client.PlayerID
+	if h.game == nil || msg.Client.Type != ClientPlayer || h.game.GetPlayer(pid) == nil {
+		h.sendError(msg.Client, "you are not playing")
+		return
+	}
+	if hints, rated := h.lobby.Settings(); rated {
+		h.sendError(msg.Client, "hints are off in rated games")
+		return
+	} else if !hints {
+		h.sendError(msg.Client, "hints are off in this game")
+		return
+	}
+
+	reply := protocol.HintsMsg{Hints: []protocol.Hint{}}
+	legal := h.game.LegalActions(pid)
+	if len(legal) > 0 {
+		advice := bot.Advise(h.game.ViewFor(pid), pid, legal)
+		for _, a := range advice[:min(len(advice), maxHints)] {
+			reply.Hints = append(reply.Hints, protocol.Hint{Action: a.Action, Reason: a.Reason, Params: a.Params})
+		}
+	}
+	msg.Client.SendEnvelope(protocol.MustEnvelope(protocol.MsgHints, reply))
+}
@@ -1,14 +1,17 @@
 package server
 
 import (
+	"citadels/internal/archive"
+	"citadels/internal/bot"
 	"citadels/internal/engine"
 	"citadels/internal/engine/abilities"
 	"citadels/internal/lobby"
 	"citadels/internal/protocol"
+	"citadels/internal/record"
 	"encoding/json"
 	"fmt"
 	"log"
-	"math/rand"
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -20,26 +23,46 @@ type Hub struct {
 	mu         sync.Mutex
 	gameID     string
 	lobby      *lobby.Lobby
+	archive    *archive.Archive
 	game       *engine.Game
+	record     *record.Record          // latest record of game, guarded by mu
+	undo       *undoVote               // open undo request, see undo.go
+	bots       map[string]bot.Strategy // by player ID, see bots.go
+	autopilots map[string]*autopilot   // people the autopilot plays for, see autopilot.go
+	premoves   map[string]*premoves    // moves people queued, see premoves.go
 	clients    map[*Client]bool
 	register   chan *Client
 	unregister chan *Client
 	incoming   chan IncomingMessage
+	internal   chan protocol.Envelope // timer and bot posts; clients can't send on it
 	quit       chan struct{}
 
 	turnTimer     *time.Timer
 	timerDeadline int64 // Unix milliseconds
+	stateSeq      int   // counts state broadcasts; tells a bot's search if it's stale
+
+	botTimer     *time.Timer
+	botSearching bool          // a Searcher bot is thinking
+	botBudget    time.Duration // search time per decision for hard bots; 0 keeps the default
+
+	autopilotLevel bot.Difficulty // strategy the autopilot plays with
 }
 
-func NewHub(gameID string, lob *lobby.Lobby) *Hub {
+func NewHub(gameID string, lob *lobby.Lobby, arch *archive.Archive) *Hub {
 	return &Hub{
 		gameID:     gameID,
 		lobby:      lob,
+		archive:    arch,
+		autopilots: make(map[string]*autopilot),
+		premoves:   make(map[string]*premoves),
 		clients:    make(map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		incoming:   make(chan IncomingMessage, 256),
+		internal:   make(chan protocol.Envelope, 16),
 		quit:       make(chan struct{}),
+
+		autopilotLevel: bot.Normal,
 	}
 }
 
@@ -53,18 +76,26 @@ func (h *Hub) Run() {
 			h.sendLobbyUpdate()
 			if h.game != nil {
 				h.sendStateToClient(client)
+				h.clientBack(client)
 			}
 
 		case client := <-h.unregister:
 			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
+			_, ok := h.clients[client]
+			if ok {
 				delete(h.clients, client)
 				close(client.send)
 			}
 			h.mu.Unlock()
+			if ok {
+				h.clientGone(client)
+			}
 
 		case msg := <-h.incoming:
-			h.handleMessage(msg)
+			h.guard(func() { h.handleMessage(msg) })
+
+		case env := <-h.internal:
+			h.guard(func() { h.handleInternal(env) })
 
 		case <-h.quit:
 			return
@@ -72,10 +103,35 @@ func (h *Hub) Run() {
 	}
 }
 
-func (h *Hub) handleMessage(msg IncomingMessage) {
-	switch msg.Envelope.Type {
+// guard runs a handler and logs a panic instead of letting it through. The
+// engine panics on its own bugs, such as an illegal phase transition, and an
+// unrecovered panic here would take down every game on the server.
+func (h *Hub) guard(handle func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("game %s: panic: %v\n%s", h.gameID, r, debug.Stack())
+		}
+	}()
+	handle()
+}
+
+// handleInternal handles what the hub's own timers and goroutines post. They
+// have their own channel, so no client can pose as a timer or a bot.
+func (h *Hub) handleInternal(env protocol.Envelope) {
+	switch env.Type {
 	case "timer_expired":
 		h.handleTimerExpired()
+	case "bot_move":
+		h.handleBotMove()
+	case "bot_action":
+		h.handleBotAction(env)
+	}
+}
+
+func (h *Hub) handleMessage(msg IncomingMessage) {
+	switch msg.Envelope.Type {
+	case protocol.MsgHello:
+		h.handleHello(msg)
 	case protocol.MsgJoin:
 		h.handleJoin(msg)
 	case protocol.MsgLeave:
@@ -84,11 +140,53 @@ func (h *Hub) handleMessage(msg IncomingMessage) {
 		h.handleReady(msg)
 	case protocol.MsgStartGame:
 		h.handleStartGame(msg)
+	case protocol.MsgPreview:
+		h.handlePreview(msg)
+	case protocol.MsgUndoRequest:
+		h.handleUndoRequest(msg)
+	case protocol.MsgUndoVote:
+		h.handleUndoVote(msg)
+	case protocol.MsgAddBot:
+		h.handleAddBot(msg)
+	case protocol.MsgRemoveBot:
+		h.handleRemoveBot(msg)
+	case protocol.MsgAutopilot:
+		h.handleAutopilot(msg)
+	case protocol.MsgSettings:
+		h.handleSettings(msg)
+	case protocol.MsgHint:
+		h.handleHint(msg)
+	case protocol.MsgPremove:
+		h.handlePremove(msg)
 	default:
 		h.handleGameAction(msg)
 	}
 }
 
+func (h *Hub) handleHello(msg IncomingMessage) {
+	var hello protocol.HelloMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &hello); err != nil {
+		h.sendError(msg.Client, "invalid hello message")
+		return
+	}
+	welcome, err := protocol.Negotiate(hello)
+	if err != nil {
+		h.sendError(msg.Client, err.Error())
+		return
+	}
+	h.mu.Lock()
+	msg.Client.setProtocol(welcome.Version, welcome.Features)
+	h.mu.Unlock()
+	if welcome.Deprecated {
+		log.Printf("client %s speaks deprecated protocol v%d", msg.Client.PlayerID, welcome.Version)
+	}
+	msg.Client.SendEnvelope(protocol.MustEnvelope(protocol.MsgWelcome, welcome))
+	h.sendPromptsToClient(msg.Client)
+	h.sendUndoToClient(msg.Client)
+	h.sendAutopilotToClient(msg.Client)
+	h.sendPremovesToClient(msg.Client)
+}
+
 func (h *Hub) handleJoin(msg IncomingMessage) {
 	var join protocol.JoinMsg
 	if err := json.Unmarshal(msg.Envelope.Payload, &join); err != nil {
@@ -100,6 +198,8 @@ func (h *Hub) handleJoin(msg IncomingMessage) {
 	// Game already in progress — just update client PlayerID, state was sent on register
 	if h.lobby.Started {
 		h.sendStateToClient(msg.Client)
+		h.sendPromptsToClient(msg.Client)
+		h.clientBack(msg.Client)
 		return
 	}
 
@@ -145,17 +245,8 @@ func (h *Hub) handleStartGame(msg IncomingMessage) {
 		players[i] = engine.NewPlayer(lp.ID, lp.Name)
 	}
 
-	reg := engine.NewAbilityRegistry()
-	reg.Register(abilities.Assassin{})
-	reg.Register(abilities.Thief{})
-	reg.Register(abilities.Magician{})
-	reg.Register(abilities.King{})
-	reg.Register(abilities.Bishop{})
-	reg.Register(abilities.Merchant{})
-	reg.Register(abilities.Architect{})
-	reg.Register(abilities.Warlord{})
-
-	h.game = engine.NewGame(players, engine.DefaultConfig(), reg)
+	h.game = engine.NewGame(players, engine.DefaultConfig(), abilities.NewRegistry())
+	h.startBots()
 	events := h.game.StartGame()
 	h.broadcastEvents(events)
 	h.broadcastState()
@@ -179,10 +270,49 @@ func (h *Hub) handleGameAction(msg IncomingMessage) {
 		return
 	}
 
+	// Acting yourself takes back control from the autopilot and pre-moves
+	h.releaseAutopilot(msg.Client.PlayerID)
+	h.setPremoves(msg.Client.PlayerID, nil)
+
 	h.broadcastEvents(events)
 	h.broadcastState()
 }
 
+// handlePreview answers a preview request. Its payload is an action payload
+// plus "action", the action type.
+func (h *Hub) handlePreview(msg IncomingMessage) {
+	if h.game == nil {
+		h.sendError(msg.Client, "game not started")
+		return
+	}
+	var req struct {
+		Action engine.ActionType `json:"action"`
+	}
+	if err := json.Unmarshal(msg.Envelope.Payload, &req); err != nil {
+		h.sendError(msg.Client, "invalid preview message")
+		return
+	}
+	action, err := h.parseAction(protocol.Envelope{Type: protocol.MsgType(req.Action), Payload: msg.Envelope.Payload})
+	if err != nil {
+		h.sendError(msg.Client, err.Error())
+		return
+	}
+
+	result := protocol.PreviewResultMsg{Action: action}
+	preview, err := h.game.Preview(msg.Client.PlayerID, action)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Events = preview.Events
+		for _, p := range preview.View.Players {
+			if p.ID == msg.Client.PlayerID {
+				result.Gold = p.Gold
+			}
+		}
+	}
+	msg.Client.SendEnvelope(protocol.MustEnvelope(protocol.MsgPreviewResult, result))
+}
+
 func (h *Hub) parseAction(env protocol.Envelope) (engine.Action, error) {
 	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(env.Payload, &raw); err != nil {
@@ -196,6 +326,9 @@ func (h *Hub) parseAction(env protocol.Envelope) (engine.Action, error) {
 		json.Unmarshal(v, &c)
 		action.Character = engine.CharacterRole(c)
 	}
+	if v, ok := raw["card_id"]; ok {
+		json.Unmarshal(v, &action.CardID)
+	}
 	if v, ok := raw["district_name"]; ok {
 		json.Unmarshal(v, &action.DistrictName)
 	}
@@ -218,7 +351,46 @@ func (h *Hub) parseAction(env protocol.Envelope) (engine.Action, error) {
 func (h *Hub) broadcastEvents(events []engine.Event) {
 	for _, ev := range events {
 		env := protocol.MustEnvelope(protocol.MsgEvent, ev)
-		h.broadcastAll(env)
+		h.broadcastFeature(protocol.FeatureEvents, env)
+	}
+	h.announce(events)
+	h.archiveResult(events)
+	h.updateRecord()
+	h.cancelUndo()
+	h.countAutopilotTurns(events)
+	h.usePremoves(events)
+}
+
+// updateRecord refreshes the record served by Record. The game itself is
+// only touched by Run's goroutine, so HTTP handlers read this copy instead.
+func (h *Hub) updateRecord() {
+	rec := record.FromGame(h.gameID, h.game)
+	h.mu.Lock()
+	h.record = &rec
+	h.mu.Unlock()
+}
+
+// Record returns the game's record so far, or false before the game starts.
+func (h *Hub) Record() (record.Record, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.record == nil {
+		return record.Record{}, false
+	}
+	return *h.record, true
+}
+
+// archiveResult saves the result once the game is over.
+func (h *Hub) archiveResult(events []engine.Event) {
+	for _, ev := range events {
+		if ev.Type != engine.EventGameOver {
+			continue
+		}
+		result := archive.FromGame(h.gameID, h.game)
+		_, result.Rated = h.lobby.Settings()
+		if err := h.archive.Save(result); err != nil {
+			log.Printf("game %s: %v", h.gameID, err)
+		}
 	}
 }
 
@@ -228,15 +400,23 @@ func (h *Hub) broadcastState() {
 		return
 	}
 
+	h.stateSeq++
 	if h.isActionablePhase() {
 		h.startTimer()
 	} else {
 		h.stopTimer()
 	}
 
+	h.sendStates()
+	h.sendPrompts()
+	h.scheduleBots()
+}
+
+// sendStates sends every client its view of the game. The lock is released
+// in a defer so a panic recovered by guard doesn't leave it held.
+func (h *Hub) sendStates() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-
 	for client := range h.clients {
 		h.sendStateToClient(client)
 	}
@@ -261,14 +441,17 @@ func (h *Hub) sendStateToClient(client *Client) {
 
 func (h *Hub) sendLobbyUpdate() {
 	players := h.lobby.GetPlayers()
+	hints, rated := h.lobby.Settings()
 	lps := make([]protocol.LobbyPlayer, len(players))
 	for i, p := range players {
-		lps[i] = protocol.LobbyPlayer{ID: p.ID, Name: p.Name, Ready: p.Ready}
+		lps[i] = protocol.LobbyPlayer{ID: p.ID, Name: p.Name, Ready: p.Ready, Bot: p.Bot}
 	}
 	env := protocol.MustEnvelope(protocol.MsgLobbyUpdate, protocol.LobbyUpdate{
 		GameID:  h.gameID,
 		Players: lps,
 		Started: h.lobby.Started,
+		Hints:   hints,
+		Rated:   rated,
 	})
 	h.broadcastAll(env)
 }
@@ -291,6 +474,28 @@ func (h *Hub) broadcastAll(env protocol.Envelope) {
 	}
 }
 
+// broadcastFeature sends a message only to clients that negotiated the feature.
+func (h *Hub) broadcastFeature(feature string, env protocol.Envelope) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("broadcast marshal error: %v", err)
+		return
+	}
+	for client := range h.clients {
+		if !client.Has(feature) {
+			continue
+		}
+		select {
+		case client.send <- data:
+		default:
+			log.Printf("client %s buffer full", client.PlayerID)
+		}
+	}
+}
+
 func (h *Hub) sendError(client *Client, message string) {
 	env := protocol.MustEnvelope(protocol.MsgError, protocol.ErrorMsg{Message: message})
 	client.SendEnvelope(env)
@@ -302,10 +507,10 @@ func (h *Hub) isActionablePhase() bool {
 		return false
 	}
 	switch h.game.Phase {
-	case engine.PhaseDraftPick, engine.PhaseDrawChoice, engine.PhasePlayerTurn:
+	case engine.PhaseDraftPick, engine.PhaseDrawChoice, engine.PhasePlayerTurn, engine.PhaseAbility:
 		return true
 	default:
-		return h.game.PendingGraveyard != nil
+		return false
 	}
 }
 
@@ -315,9 +520,7 @@ func (h *Hub) startTimer() {
 	}
 	h.timerDeadline = time.Now().Add(turnTimerDuration).UnixMilli()
 	h.turnTimer = time.AfterFunc(turnTimerDuration, func() {
-		h.incoming <- IncomingMessage{
-			Envelope: protocol.Envelope{Type: "timer_expired"},
-		}
+		h.internal <- protocol.Envelope{Type: "timer_expired"}
 	})
 }
 
@@ -328,78 +531,3 @@ func (h *Hub) stopTimer() {
 	}
 	h.timerDeadline = 0
 }
-
-func (h *Hub) handleTimerExpired() {
-	if h.game == nil {
-		return
-	}
-
-	var events []engine.Event
-	var err error
-
-	switch {
-	case h.game.PendingGraveyard != nil:
-		// Auto-decline graveyard
-		pid := h.game.PendingGraveyard.PlayerID
-		events, err = h.game.Apply(pid, engine.Action{
-			Type:      engine.ActionGraveyardRespond,
-			ExtraData: "decline",
-		})
-
-	case h.game.Phase == engine.PhaseDraftPick && h.game.Draft != nil:
-		// Auto-pick random available character
-		pid := h.game.Draft.CurrentPickerID()
-		if pid == "" {
-			return
-		}
-		role := h.game.Draft.Available[rand.Intn(len(h.game.Draft.Available))]
-		events, err = h.game.Apply(pid, engine.Action{
-			Type:      engine.ActionDraftPick,
-			Character: role,
-		})
-
-	case h.game.Phase == engine.PhaseDrawChoice:
-		// Auto-keep first card
-		pid := h.game.CurrentTurnPlayer
-		events, err = h.game.Apply(pid, engine.Action{
-			Type:  engine.ActionKeepCard,
-			Index: 0,
-		})
-
-	case h.game.Phase == engine.PhasePlayerTurn:
-		pid := h.game.CurrentTurnPlayer
-		p := h.game.GetPlayer(pid)
-		if p == nil {
-			return
-		}
-		if !p.TookAction {
-			// Take gold first
-			events, err = h.game.Apply(pid, engine.Action{Type: engine.ActionTakeGold})
-			if err != nil {
-				log.Printf("timer auto take_gold error: %v", err)
-				return
-			}
-			h.broadcastEvents(events)
-		}
-		// Collect gold for matching districts (free action, always beneficial)
-		if !p.CollectedGold {
-			collectEvents, collectErr := h.game.Apply(pid, engine.Action{Type: engine.ActionCollectGold})
-			if collectErr == nil {
-				h.broadcastEvents(collectEvents)
-			}
-		}
-		// End turn
-		events, err = h.game.Apply(pid, engine.Action{Type: engine.ActionEndTurn})
-
-	default:
-		return
-	}
-
-	if err != nil {
-		log.Printf("timer auto-action error: %v", err)
-		return
-	}
-
-	h.broadcastEvents(events)
-	h.broadcastState()
-}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"citadels/internal/archive"
+	"citadels/internal/engine"
+	"citadels/internal/engine/abilities"
+	"citadels/internal/lobby"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"testing"
+)
+
+// The hub's state machines are tested without its loop: tests call the
+// handlers directly on their own goroutine, as Run would, and read what the
+// fake clients were sent.
+
+// newTestHub starts a seeded game for the given player IDs, all people.
+func newTestHub(t *testing.T, seed uint64, players ...string) *Hub {
+	t.Helper()
+	lob := lobby.NewLobby("test")
+	for _, id := range players {
+		if err := lob.Join(id, id); err != nil {
+			t.Fatalf("join %s: %v", id, err)
+		}
+		lob.SetReady(id, true)
+	}
+	if err := lob.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	arch, _ := archive.New("")
+	h := NewHub("test", lob, arch)
+	t.Cleanup(func() {
+		h.stopTimer()
+		if h.botTimer != nil {
+			h.botTimer.Stop()
+		}
+	})
+
+	var ps []*engine.Player
+	for _, id := range players {
+		ps = append(ps, engine.NewPlayer(id, id))
+	}
+	cfg := engine.DefaultConfig()
+	cfg.Seed = seed
+	h.game = engine.NewGame(ps, cfg, abilities.NewRegistry())
+	h.startBots()
+	h.broadcastEvents(h.game.StartGame())
+	h.broadcastState()
+	return h
+}
+
+// connect adds a player's phone that negotiated the given features.
+func connect(h *Hub, playerID string, features ...string) *Client {
+	c := &Client{hub: h, send: make(chan []byte, 256), PlayerID: playerID, Type: ClientPlayer}
+	c.setProtocol(protocol.ProtocolVersion, features)
+	h.clients[c] = true
+	return c
+}
+
+// post hands the hub a message from the client.
+func post(h *Hub, c *Client, typ protocol.MsgType, payload any) {
+	h.handleMessage(IncomingMessage{Client: c, Envelope: protocol.MustEnvelope(typ, payload)})
+}
+
+// received drains the client's messages of the given type.
+func received(c *Client, typ protocol.MsgType) []protocol.Envelope {
+	var out []protocol.Envelope
+	for {
+		select {
+		case data := <-c.send:
+			var env protocol.Envelope
+			json.Unmarshal(data, &env)
+			if env.Type == typ {
+				out = append(out, env)
+			}
+		default:
+			return out
+		}
+	}
+}
+
+// actor returns the first player with a legal action.
+func actor(h *Hub) string {
+	for _, p := range h.game.Players {
+		if len(h.game.LegalActions(p.ID)) > 0 {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+func TestGuard(t *testing.T) {
+	h := newTestHub(t, 12, "a", "b")
+	h.guard(func() { panic("engine bug") })
+
+	// The hub keeps serving after a panic
+	c := connect(h, "a", protocol.FeaturePrompts)
+	h.guard(h.broadcastState)
+	if len(received(c, protocol.MsgPlayerState)) != 1 {
+		t.Error("no state after a recovered panic")
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// premoves are moves a person queued before their pick or turn came up.
+// While they cover what the player has to decide, they play for the player
+// like a bot seat (see bots.go); the turn timer never hands such a player to
+// the autopilot. Acting yourself clears the queue.
+type premoves struct {
+	draft []engine.CharacterRole // by preference; for the player's picks until the draft ends
+	pass  bool                   // take gold and end the player's next turn
+}
+
+// next returns the queued move for legal, if there is one.
+func (p *premoves) next(legal []engine.Action) (engine.Action, bool) {
+	for _, r := range p.draft {
+		for _, a := range legal {
+			if a.Type == engine.ActionDraftPick && a.Character == r {
+				return a, true
+			}
+		}
+	}
+	if p.pass {
+		for _, t := range []engine.ActionType{engine.ActionTakeGold, engine.ActionEndTurn} {
+			for _, a := range legal {
+				if a.Type == t {
+					return a, true
+				}
+			}
+		}
+	}
+	return engine.Action{}, false
+}
+
+func (p *premoves) empty() bool {
+	return len(p.draft) == 0 && !p.pass
+}
+
+// Act makes premoves a bot.Strategy. It is only asked when next has a move.
+func (p *premoves) Act(view engine.PlayerViewData, legal []engine.Action) engine.Action {
+	a, _ := p.next(legal)
+	return a
+}
+
+// premovesFor returns the player's pre-moves if they cover what the player
+// has to decide right now.
+func (h *Hub) premovesFor(playerID string) *premoves {
+	p := h.premoves[playerID]
+	if p == nil {
+		return nil
+	}
+	if _, ok := p.next(h.game.LegalActions(playerID)); !ok {
+		return nil
+	}
+	return p
+}
+
+func (h *Hub) handlePremove(msg IncomingMessage) {
+	var req protocol.PremoveMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &req); err != nil {
+		h.sendError(msg.Client, "invalid premove message")
+		return
+	}
+	pid := msg.Client.PlayerID
+	if h.game == nil || h.game.Phase == engine.PhaseGameOver || h.game.GetPlayer(pid) == nil {
+		h.sendError(msg.Client, "you are not playing")
+		return
+	}
+	p := &premoves{pass: req.Pass}
+	for _, name := range req.Draft {
+		r := engine.ParseRole(name)
+		if !slices.Contains(h.game.Config.Roster(), r) {
+			h.sendError(msg.Client, fmt.Sprintf("unknown character %q", name))
+			return
+		}
+		if !slices.Contains(p.draft, r) {
+			p.draft = append(p.draft, r)
+		}
+	}
+	if p.empty() {
+		h.setPremoves(pid, nil)
+		return
+	}
+	h.setPremoves(pid, p)
+	h.scheduleBots()
+}
+
+// setPremoves replaces the player's pre-moves and tells their phones.
+func (h *Hub) setPremoves(playerID string, p *premoves) {
+	if p == nil && h.premoves[playerID] == nil {
+		return
+	}
+	if p == nil {
+		delete(h.premoves, playerID)
+	} else {
+		h.premoves[playerID] = p
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		if c.Type == ClientPlayer && c.PlayerID == playerID {
+			h.sendPremovesToClient(c)
+		}
+	}
+}
+
+// usePremoves drops the pre-moves the events used up: preference lists once
+// the draft is over, a pass once the player's turn ends.
+func (h *Hub) usePremoves(events []engine.Event) {
+	for _, ev := range events {
+		switch ev.Type {
+		case engine.EventDraftDone:
+			for pid, p := range h.premoves {
+				if len(p.draft) > 0 {
+					h.keepPremoves(pid, premoves{pass: p.pass})
+				}
+			}
+		case engine.EventTurnEnd:
+			if p := h.premoves[ev.Player]; p != nil && p.pass {
+				h.keepPremoves(ev.Player, premoves{draft: p.draft})
+			}
+		}
+	}
+}
+
+// keepPremoves replaces the player's pre-moves with what is left of them.
+func (h *Hub) keepPremoves(playerID string, rest premoves) {
+	if rest.empty() {
+		h.setPremoves(playerID, nil)
+	} else {
+		h.setPremoves(playerID, &rest)
+	}
+}
+
+// sendPremovesToClient tells a player's phone what they have queued.
+func (h *Hub) sendPremovesToClient(client *Client) {
+	if h.game == nil || client.Type != ClientPlayer || !client.Has(protocol.FeaturePremoves) {
+		return
+	}
+	msg := protocol.PremovesMsg{Draft: []string{}}
+	if p := h.premoves[client.PlayerID]; p != nil {
+		for _, r := range p.draft {
+			msg.Draft = append(msg.Draft, r.String())
+		}
+		msg.Pass = p.pass
+	}
+	client.SendEnvelope(protocol.MustEnvelope(protocol.MsgPremoves, msg))
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestPremoveDraft(t *testing.T) {
+	h := newTestHub(t, 5, "a", "b")
+	var prefs []string
+	roster := h.game.Config.Roster()
+	for i := len(roster) - 1; i >= 0; i-- {
+		prefs = append(prefs, roster[i].String())
+	}
+	for _, p := range h.game.Players {
+		post(h, connect(h, p.ID), protocol.MsgPremove, protocol.PremoveMsg{Draft: prefs})
+	}
+
+	for h.game.Phase == engine.PhaseDraftPick {
+		pid := actor(h)
+		legal := h.game.LegalActions(pid)
+		var want engine.CharacterRole
+		for _, name := range prefs {
+			r := engine.ParseRole(name)
+			if slices.ContainsFunc(legal, func(a engine.Action) bool { return a.Character == r }) {
+				want = r
+				break
+			}
+		}
+		h.handleBotMove()
+		picks := h.game.Draft.Picks[pid]
+		if h.game.Phase == engine.PhaseDraftPick && (len(picks) == 0 || picks[len(picks)-1] != want) {
+			t.Fatalf("%s picked %v, want their preference %v", pid, picks, want)
+		}
+	}
+	if len(h.premoves) != 0 {
+		t.Errorf("preferences outlived the draft: %+v", h.premoves)
+	}
+}
+
+func TestPremoveUnknownCharacter(t *testing.T) {
+	h := newTestHub(t, 6, "a", "b")
+	c := connect(h, "a")
+	post(h, c, protocol.MsgPremove, protocol.PremoveMsg{Draft: []string{"Jester"}})
+	if len(received(c, protocol.MsgError)) != 1 {
+		t.Error("no error for an unknown character")
+	}
+	if h.premoves["a"] != nil {
+		t.Error("a refused premove was queued")
+	}
+}
+
+func TestPremovePass(t *testing.T) {
+	h := newTestHub(t, 7, "a", "b")
+	for h.game.Phase == engine.PhaseDraftPick {
+		h.applyBotAction(actor(h), h.game.LegalActions(actor(h))[0])
+	}
+	pid := actor(h)
+	if h.game.Phase != engine.PhasePlayerTurn {
+		t.Fatalf("phase %v after the draft", h.game.Phase)
+	}
+	c := connect(h, pid, protocol.FeaturePremoves)
+	post(h, c, protocol.MsgPremove, protocol.PremoveMsg{Pass: true})
+	gold := h.game.GetPlayer(pid).Gold
+
+	for i := 0; h.premoves[pid] != nil && i < 5; i++ {
+		h.handleBotMove()
+	}
+	if h.premoves[pid] != nil {
+		t.Fatal("the pass was never played")
+	}
+	if g := h.game.GetPlayer(pid).Gold; g < gold+2 {
+		t.Errorf("gold %d → %d, want gold taken", gold, g)
+	}
+	if actor(h) == pid {
+		t.Error("the turn didn't end")
+	}
+	env := received(c, protocol.MsgPremoves)
+	var last protocol.PremovesMsg
+	json.Unmarshal(env[len(env)-1].Payload, &last)
+	if last.Pass {
+		t.Error("the phone still shows the pass")
+	}
+}
+
+func TestPremoveClearedByAction(t *testing.T) {
+	h := newTestHub(t, 8, "a", "b")
+	picker := h.game.Draft.CurrentPickerID()
+	c := connect(h, picker, protocol.FeaturePremoves)
+	other := connect(h, picker)
+	post(h, c, protocol.MsgPremove, protocol.PremoveMsg{Pass: true})
+	if got := received(c, protocol.MsgPremoves); len(got) != 1 {
+		t.Fatalf("got %d premoves messages, want 1", len(got))
+	}
+	if len(received(other, protocol.MsgPremoves)) != 0 {
+		t.Error("a phone without the feature got premoves")
+	}
+
+	// The pass doesn't cover a pick, so the timer takes over as usual
+	if h.strategyFor(picker) != nil {
+		t.Fatal("a pass plays the draft")
+	}
+
+	post(h, c, protocol.MsgEndTurn, struct{}{})
+	if h.premoves[picker] == nil {
+		t.Fatal("a refused action cleared the pre-moves")
+	}
+	post(h, c, protocol.MsgDraftPickAction, map[string]any{"character": h.game.LegalActions(picker)[0].Character})
+	if h.premoves[picker] != nil {
+		t.Error("acting didn't clear the pre-moves")
+	}
+}
+
+func TestPremoveKeepsAutopilotAway(t *testing.T) {
+	h := newTestHub(t, 9, "a", "b")
+	picker := h.game.Draft.CurrentPickerID()
+	var all []string
+	for _, r := range h.game.Config.Roster() {
+		all = append(all, r.String())
+	}
+	post(h, connect(h, picker), protocol.MsgPremove, protocol.PremoveMsg{Draft: all})
+
+	h.handleTimerExpired()
+	if h.autopilots[picker] != nil {
+		t.Error("the timer handed a player with pre-moves to the autopilot")
+	}
+	if len(h.game.Draft.Picks[picker]) != 1 {
+		t.Error("the pre-move wasn't played when the timer ran out")
+	}
+}
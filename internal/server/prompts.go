@@ -0,0 +1,134 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"strings"
+)
+
+// sendPrompts sends targeted prompts to every client whose player must act.
+// Only clients that negotiated the prompts feature receive them.
+func (h *Hub) sendPrompts() {
+	if h.game == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients {
+		h.sendPromptsToClient(client)
+	}
+}
+
+func (h *Hub) sendPromptsToClient(client *Client) {
+	if h.game == nil || client.Type != ClientPlayer || !client.Has(protocol.FeaturePrompts) {
+		return
+	}
+	for _, env := range h.promptsFor(client.PlayerID) {
+		client.SendEnvelope(env)
+	}
+}
+
+// promptsFor builds the prompts for one player. It returns nil if the
+// player has nothing to decide right now.
+func (h *Hub) promptsFor(playerID string) []protocol.Envelope {
+	g := h.game
+	view := g.ViewFor(playerID)
+	legal := g.LegalActions(playerID)
+	deadline := h.timerDeadline
+	var out []protocol.Envelope
+
+	if d := view.Decision; d != nil {
+		out = append(out, protocol.MustEnvelope(protocol.MsgAbilityPrompt, protocol.AbilityPromptMsg{
+			Ability:  d.Kind,
+			Options:  d.Options,
+			District: d.Card,
+			Deadline: deadline,
+		}))
+	}
+
+	switch {
+	case g.Phase == engine.PhaseDraftPick && len(view.DraftChoices) > 0:
+		out = append(out, protocol.MustEnvelope(protocol.MsgYourTurn, protocol.YourTurnMsg{
+			Phase:      g.Phase.String(),
+			Actions:    []string{string(engine.ActionDraftPick)},
+			Characters: view.DraftChoices,
+			Deadline:   deadline,
+		}))
+
+	case g.Phase == engine.PhaseDrawChoice && view.IsMyTurn:
+		out = append(out, protocol.MustEnvelope(protocol.MsgDrawChoice, protocol.DrawChoiceMsg{
+			Cards:    view.DrawnCards,
+			Keep:     view.KeepCount,
+			Deadline: deadline,
+		}))
+
+	case g.Phase == engine.PhasePlayerTurn && view.IsMyTurn:
+		out = append(out, protocol.MustEnvelope(protocol.MsgYourTurn, protocol.YourTurnMsg{
+			Phase:     g.Phase.String(),
+			Role:      view.CurrentRole,
+			Actions:   turnActions(legal),
+			Buildable: buildable(view, legal),
+			Deadline:  deadline,
+		}))
+		if view.CanUseAbility && len(view.ValidTargets) > 0 {
+			out = append(out, protocol.MustEnvelope(protocol.MsgAbilityPrompt, protocol.AbilityPromptMsg{
+				Ability:  strings.ToLower(view.CurrentRole),
+				Role:     view.CurrentRole,
+				Options:  view.ValidTargets,
+				Targets:  view.AbilityTargets,
+				Deadline: deadline,
+			}))
+		}
+	}
+	return out
+}
+
+// turnActions lists the distinct action types among the legal actions.
+func turnActions(legal []engine.Action) []string {
+	seen := map[engine.ActionType]bool{}
+	var out []string
+	for _, a := range legal {
+		if seen[a.Type] {
+			continue
+		}
+		seen[a.Type] = true
+		out = append(out, string(a.Type))
+	}
+	return out
+}
+
+// buildable returns the hand cards named by legal build actions.
+func buildable(view engine.PlayerViewData, legal []engine.Action) []engine.District {
+	can := map[int]bool{}
+	for _, a := range legal {
+		if a.Type == engine.ActionBuild {
+			can[a.CardID] = true
+		}
+	}
+	var out []engine.District
+	for _, d := range view.Hand {
+		if can[d.ID] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// announce translates engine events into broadcast prompt messages.
+func (h *Hub) announce(events []engine.Event) {
+	for i, ev := range events {
+		switch data := ev.Data.(type) {
+		case engine.CharacterCallData:
+			msg := protocol.CharacterCalledMsg{Role: data.Role, Number: data.Number, Player: data.Player}
+			// A murdered character's call is immediately followed by EventMurdered.
+			if i+1 < len(events) && events[i+1].Type == engine.EventMurdered {
+				msg.Murdered = true
+			}
+			h.broadcastFeature(protocol.FeaturePrompts, protocol.MustEnvelope(protocol.MsgCharacterCalled, msg))
+		case engine.GameOverData:
+			h.broadcastFeature(protocol.FeaturePrompts, protocol.MustEnvelope(protocol.MsgGameOver,
+				protocol.GameOverMsg{Scores: data.Scores, Winners: data.Winners, Stats: data.Stats}))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestPromptsDraft(t *testing.T) {
+	h := newTestHub(t, 10, "a", "b")
+	picker := h.game.Draft.CurrentPickerID()
+	waiting := "a"
+	if picker == "a" {
+		waiting = "b"
+	}
+	c := connect(h, picker, protocol.FeaturePrompts)
+	old := connect(h, picker)
+	other := connect(h, waiting, protocol.FeaturePrompts)
+
+	h.sendPrompts()
+	got := received(c, protocol.MsgYourTurn)
+	if len(got) != 1 {
+		t.Fatalf("picker got %d your_turn, want 1", len(got))
+	}
+	var msg protocol.YourTurnMsg
+	json.Unmarshal(got[0].Payload, &msg)
+	if len(msg.Characters) == 0 || !slices.Equal(msg.Actions, []string{string(engine.ActionDraftPick)}) {
+		t.Errorf("draft prompt %+v", msg)
+	}
+	if len(received(other, protocol.MsgYourTurn)) != 0 {
+		t.Error("a player who isn't picking was prompted")
+	}
+	if len(received(old, protocol.MsgYourTurn)) != 0 {
+		t.Error("a phone without the prompts feature was prompted")
+	}
+}
+
+func TestPromptsTurn(t *testing.T) {
+	h := newTestHub(t, 11, "a", "b")
+	clients := map[string]*Client{}
+	for _, p := range h.game.Players {
+		clients[p.ID] = connect(h, p.ID, protocol.FeaturePrompts)
+	}
+	for h.game.Phase == engine.PhaseDraftPick {
+		h.applyBotAction(actor(h), h.game.LegalActions(actor(h))[0])
+	}
+	pid := actor(h)
+	c := clients[pid]
+	if len(received(c, protocol.MsgCharacterCalled)) == 0 {
+		t.Error("no character_called once the draft was over")
+	}
+
+	h.sendPrompts()
+	got := received(c, protocol.MsgYourTurn)
+	if len(got) != 1 {
+		t.Fatalf("player on turn got %d your_turn, want 1", len(got))
+	}
+	var msg protocol.YourTurnMsg
+	json.Unmarshal(got[0].Payload, &msg)
+	for _, a := range []engine.ActionType{engine.ActionTakeGold, engine.ActionDrawCards} {
+		if !slices.Contains(msg.Actions, string(a)) {
+			t.Errorf("turn prompt %v lacks %s", msg.Actions, a)
+		}
+	}
+	if msg.Role == "" {
+		t.Error("turn prompt has no role")
+	}
+}
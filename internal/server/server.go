@@ -1,11 +1,14 @@
 package server
 
 import (
+	"citadels/internal/archive"
+	"citadels/internal/bot"
 	"embed"
 	"fmt"
 	"io/fs"
 	"log"
 	"net/http"
+	"time"
 )
 
 // Server ties together HTTP serving and WebSocket handling.
@@ -15,9 +18,12 @@ type Server struct {
 	static   embed.FS
 }
 
-func New(port int, static embed.FS) *Server {
+func New(port int, static embed.FS, arch *archive.Archive, botBudget time.Duration, autopilot bot.Difficulty) *Server {
+	handlers := NewHandlers(port, arch)
+	handlers.BotBudget = botBudget
+	handlers.Autopilot = autopilot
 	return &Server{
-		handlers: NewHandlers(port),
+		handlers: handlers,
 		port:     port,
 		static:   static,
 	}
@@ -35,6 +41,13 @@ func (s *Server) Start() error {
 
 	// API routes
 	mux.HandleFunc("/api/games", s.handlers.HandleListGames)
+	mux.HandleFunc("/api/games/{id}/result", s.handlers.HandleResult)
+	mux.HandleFunc("/api/games/{id}/summary.png", s.handlers.HandleSummaryPNG)
+	mux.HandleFunc("/api/games/{id}/record", s.handlers.HandleRecord)
+	mux.HandleFunc("/api/games/{id}/replay", s.handlers.HandleReplay)
+	mux.HandleFunc("/api/games/{id}/replay/{step}", s.handlers.HandleReplayFrame)
+	mux.HandleFunc("/api/records/import", s.handlers.HandleImportRecord)
+	mux.HandleFunc("/api/results", s.handlers.HandleListResults)
 	mux.HandleFunc("/api/create", s.handlers.HandleCreateGame)
 	mux.HandleFunc("/api/qr", s.handlers.HandleQR)
 	mux.HandleFunc("/api/player-id", s.handlers.HandlePlayerID)
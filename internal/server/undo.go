@@ -0,0 +1,137 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"slices"
+)
+
+// undoVote is an open request to take back a player's last action. It needs
+// every other player's approval. Bots don't vote; they agree. The TV doesn't
+// vote either: anyone can open a TV connection, so it can't speak for the host.
+type undoVote struct {
+	playerID string
+	action   engine.Action
+	voters   []string        // player IDs whose approval is needed
+	approved map[string]bool // voters who approved so far
+}
+
+func (h *Hub) handleUndoRequest(msg IncomingMessage) {
+	if h.game == nil {
+		h.sendError(msg.Client, "game not started")
+		return
+	}
+	if h.undo != nil {
+		h.sendError(msg.Client, "an undo request is already open")
+		return
+	}
+	pid := msg.Client.PlayerID
+	if err := h.game.CanUndo(pid); err != nil {
+		h.sendError(msg.Client, err.Error())
+		return
+	}
+
+	moves := h.game.Moves()
+	v := &undoVote{
+		playerID: pid,
+		action:   moves[len(moves)-1].Action,
+		approved: make(map[string]bool),
+	}
+	for _, p := range h.game.Players {
+		if p.ID != pid && !h.isBot(p.ID) {
+			v.voters = append(v.voters, p.ID)
+		}
+	}
+	if len(v.voters) == 0 {
+		h.applyUndo(v)
+		return
+	}
+	h.undo = v
+	h.broadcastFeature(protocol.FeatureUndo, h.undoRequestedEnvelope())
+}
+
+func (h *Hub) handleUndoVote(msg IncomingMessage) {
+	v := h.undo
+	if v == nil {
+		h.sendError(msg.Client, "no undo request open")
+		return
+	}
+	var vote protocol.UndoVoteMsg
+	if err := json.Unmarshal(msg.Envelope.Payload, &vote); err != nil {
+		h.sendError(msg.Client, "invalid undo vote")
+		return
+	}
+
+	if msg.Client.Type != ClientPlayer || !slices.Contains(v.voters, msg.Client.PlayerID) {
+		h.sendError(msg.Client, "you can't vote on this undo")
+		return
+	}
+	if !vote.Approve {
+		by := msg.Client.PlayerID
+		if p := h.game.GetPlayer(by); p != nil {
+			by = p.Name
+		}
+		h.closeUndo(protocol.UndoResultMsg{Reason: "rejected", By: by})
+		return
+	}
+
+	v.approved[msg.Client.PlayerID] = true
+	if len(v.approved) < len(v.voters) {
+		return
+	}
+
+	h.undo = nil
+	h.applyUndo(v)
+}
+
+// applyUndo takes back the action and tells everyone.
+func (h *Hub) applyUndo(v *undoVote) {
+	result := protocol.UndoResultMsg{PlayerID: v.playerID, Action: v.action}
+	if _, err := h.game.Undo(v.playerID); err != nil {
+		result.Reason = err.Error()
+		h.broadcastFeature(protocol.FeatureUndo, protocol.MustEnvelope(protocol.MsgUndoResult, result))
+		h.scheduleBots()
+		return
+	}
+	result.Undone = true
+	h.broadcastFeature(protocol.FeatureUndo, protocol.MustEnvelope(protocol.MsgUndoResult, result))
+	h.updateRecord()
+	h.broadcastState()
+}
+
+// cancelUndo drops the open request once the game has moved on.
+func (h *Hub) cancelUndo() {
+	if h.undo != nil {
+		h.closeUndo(protocol.UndoResultMsg{Reason: "cancelled"})
+	}
+}
+
+// closeUndo ends the open request without undoing anything.
+func (h *Hub) closeUndo(result protocol.UndoResultMsg) {
+	result.PlayerID = h.undo.playerID
+	result.Action = h.undo.action
+	h.undo = nil
+	h.broadcastFeature(protocol.FeatureUndo, protocol.MustEnvelope(protocol.MsgUndoResult, result))
+	h.scheduleBots()
+}
+
+func (h *Hub) undoRequestedEnvelope() protocol.Envelope {
+	v := h.undo
+	msg := protocol.UndoRequestedMsg{
+		PlayerID: v.playerID,
+		Action:   v.action,
+		Voters:   v.voters,
+	}
+	if p := h.game.GetPlayer(v.playerID); p != nil {
+		msg.PlayerName = p.Name
+	}
+	return protocol.MustEnvelope(protocol.MsgUndoRequested, msg)
+}
+
+// sendUndoToClient repeats the open request to a client that reconnected.
+func (h *Hub) sendUndoToClient(client *Client) {
+	if h.undo != nil && client.Has(protocol.FeatureUndo) {
+		client.SendEnvelope(h.undoRequestedEnvelope())
+	}
+}
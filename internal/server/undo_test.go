@@ -0,0 +1,41 @@
+package server
+
+import (
+	"citadels/internal/engine"
+	"citadels/internal/protocol"
+	"encoding/json"
+	"testing"
+)
+
+func TestUndoVoteExpires(t *testing.T) {
+	h := newTestHub(t, 14, "a", "b", "c")
+	for h.game.Phase == engine.PhaseDraftPick {
+		h.applyBotAction(actor(h), h.game.LegalActions(actor(h))[0])
+	}
+	pid := actor(h)
+	c := connect(h, pid, protocol.FeatureUndo)
+	post(h, c, protocol.MsgTakeGold, struct{}{})
+	post(h, c, protocol.MsgUndoRequest, struct{}{})
+	if h.undo == nil {
+		t.Fatalf("no undo vote open: %+v", received(c, protocol.MsgError))
+	}
+	moves := len(h.game.Moves())
+
+	// Nobody votes before the turn timer runs out
+	h.handleTimerExpired()
+	if h.undo != nil {
+		t.Fatal("the vote outlived the turn timer")
+	}
+	got := received(c, protocol.MsgUndoResult)
+	if len(got) != 1 {
+		t.Fatalf("got %d undo results, want 1", len(got))
+	}
+	var res protocol.UndoResultMsg
+	json.Unmarshal(got[0].Payload, &res)
+	if res.Undone || res.Reason != "expired" {
+		t.Errorf("undo result %+v, want expired", res)
+	}
+	if h.autopilots[pid] == nil || len(h.game.Moves()) != moves+1 {
+		t.Error("the autopilot didn't move for the player the table was waiting on")
+	}
+}
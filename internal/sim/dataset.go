@@ -0,0 +1,247 @@
+package sim
+
+import (
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxOpponents is how many opponents the features describe; smaller tables
+// leave the rest zero.
+const maxOpponents = 6
+
+// Decision is one line of a self-play dataset: what a bot saw, what it could
+// do and what it did, labelled with how its game ended. Decisions of
+// unfinished games are left out.
+type Decision struct {
+	Game     uint64         `json:"game"` // the game's seed
+	Step     int            `json:"step"` // actions applied before this one
+	Player   string         `json:"player"`
+	Bot      bot.Difficulty `json:"bot"`
+	Features []float64      `json:"features"` // see FeatureNames
+	Legal    []Option       `json:"legal"`
+	Chosen   int            `json:"chosen"` // index into Legal
+	Action   engine.Action  `json:"action"` // as applied; may narrow a Magician discard
+
+	Score int  `json:"score"` // the player's final score
+	Rank  int  `json:"rank"`
+	Won   bool `json:"won"`
+}
+
+// Option is a legal action. Ability actions carry their structured target.
+type Option struct {
+	Action engine.Action  `json:"action"`
+	Target *engine.Target `json:"target,omitempty"`
+}
+
+// FeatureNames names Decision.Features, in order.
+var FeatureNames = newFeatures(engine.PlayerViewData{}, "").names
+
+// Featurize turns a player's view into a fixed-length vector of numbers:
+// the phase, the player's own gold, hand, city and characters, the draft,
+// and the public state of up to six opponents in seating order from the
+// player's left.
+func Featurize(view engine.PlayerViewData, playerID string) []float64 {
+	return newFeatures(view, playerID).values
+}
+
+type features struct {
+	names  []string
+	values []float64
+}
+
+func (f *features) add(name string, v float64) {
+	f.names = append(f.names, name)
+	f.values = append(f.values, v)
+}
+
+func (f *features) flag(name string, b bool) {
+	v := 0.0
+	if b {
+		v = 1
+	}
+	f.add(name, v)
+}
+
+func newFeatures(view engine.PlayerViewData, playerID string) *features {
+	f := &features{}
+	f.flag("phase_draft", view.Phase == engine.PhaseDraftPick.String())
+	f.flag("phase_draw_choice", view.Phase == engine.PhaseDrawChoice.String())
+	f.flag("phase_turn", view.Phase == engine.PhasePlayerTurn.String())
+	f.flag("decision", view.Decision != nil)
+	f.add("round", float64(view.Round))
+	f.add("deck_size", float64(view.DeckSize))
+	f.add("current_role", float64(engine.ParseRole(view.CurrentRole)))
+	f.add("murdered_role", float64(engine.ParseRole(view.MurderedRole)))
+	f.add("robbed_role", float64(engine.ParseRole(view.RobbedRole)))
+
+	seat := slices.IndexFunc(view.Players, func(p engine.PublicPlayerData) bool { return p.ID == playerID })
+	var me engine.PublicPlayerData
+	if seat >= 0 {
+		me = view.Players[seat]
+	}
+	handValue, affordable := 0, 0
+	for _, d := range view.Hand {
+		handValue += d.Cost
+		if d.Cost <= me.Gold {
+			affordable++
+		}
+	}
+	f.add("gold", float64(me.Gold))
+	f.add("hand_size", float64(len(view.Hand)))
+	f.add("hand_value", float64(handValue))
+	f.add("hand_affordable", float64(affordable))
+	f.add("city_size", float64(len(me.City)))
+	f.add("city_value", float64(cityValue(me.City)))
+	for _, c := range []engine.DistrictColor{engine.ColorNoble, engine.ColorReligious, engine.ColorTrade, engine.ColorMilitary, engine.ColorSpecial} {
+		n := 0
+		for _, d := range me.City {
+			if d.Color == c {
+				n++
+			}
+		}
+		f.add("city_"+strings.ToLower(c.String()), float64(n))
+	}
+	f.flag("crown", me.HasCrown)
+	for _, r := range engine.AllRoles() {
+		f.flag("holds_"+strings.ToLower(r.String()), slices.Contains(view.Characters, r.String()))
+	}
+	for _, r := range engine.AllRoles() {
+		f.flag("face_up_"+strings.ToLower(r.String()), slices.Contains(view.DraftFaceUp, r.String()))
+	}
+	f.add("draft_available", float64(view.DraftAvailable))
+	f.flag("can_build", view.CanBuild)
+	f.flag("can_use_ability", view.CanUseAbility)
+	f.flag("can_take_action", view.CanTakeAction)
+	f.add("collect_gold_amount", float64(view.CollectGoldAmount))
+	f.add("drawn_cards", float64(len(view.DrawnCards)))
+	f.add("keep_count", float64(view.KeepCount))
+
+	projected := map[string]int{}
+	for _, e := range view.Projection {
+		projected[e.PlayerID] = e.Total
+	}
+	best := 0
+	for id, total := range projected {
+		if id != playerID {
+			best = max(best, total)
+		}
+	}
+	f.add("projected_score", float64(projected[playerID]))
+	f.add("best_opponent_projection", float64(best))
+
+	for i := 1; i <= maxOpponents; i++ {
+		var p engine.PublicPlayerData
+		if seat >= 0 && i < len(view.Players) {
+			p = view.Players[(seat+i)%len(view.Players)]
+		}
+		revealed := 0
+		for _, name := range p.RevealedRoles {
+			revealed = max(revealed, int(engine.ParseRole(name)))
+		}
+		prefix := fmt.Sprintf("opp%d_", i)
+		f.add(prefix+"gold", float64(p.Gold))
+		f.add(prefix+"hand_size", float64(p.HandSize))
+		f.add(prefix+"city_size", float64(len(p.City)))
+		f.add(prefix+"city_value", float64(cityValue(p.City)))
+		f.flag(prefix+"crown", p.HasCrown)
+		f.add(prefix+"revealed_role", float64(revealed))
+		f.add(prefix+"projected_score", float64(projected[p.ID]))
+	}
+	return f
+}
+
+func cityValue(city []engine.District) int {
+	v := 0
+	for _, d := range city {
+		v += d.Cost
+	}
+	return v
+}
+
+// decision records a bot about to play a in g.
+func decision(g *engine.Game, playerID string, d bot.Difficulty, legal []engine.Action, a engine.Action) Decision {
+	dec := Decision{
+		Game:     g.Seed,
+		Step:     len(g.Moves()),
+		Player:   playerID,
+		Bot:      d,
+		Features: Featurize(g.ViewFor(playerID), playerID),
+		Chosen:   -1,
+		Action:   a,
+	}
+	for i, l := range legal {
+		o := Option{Action: l}
+		if l.Type == engine.ActionAbility {
+			t := g.TargetOf(l)
+			o.Target = &t
+		}
+		dec.Legal = append(dec.Legal, o)
+		if dec.Chosen < 0 && sameOption(l, a) {
+			dec.Chosen = i
+		}
+	}
+	return dec
+}
+
+// sameOption reports whether a is the legal action l, ignoring which cards
+// a Magician discard selects.
+func sameOption(l, a engine.Action) bool {
+	return l.Type == a.Type && l.Character == a.Character && l.CardID == a.CardID &&
+		l.Target == a.Target && l.Index == a.Index && l.ExtraData == a.ExtraData
+}
+
+// label fills in how the game ended for each decision. It returns nil for
+// an unfinished game.
+func label(g *engine.Game, decisions []Decision) []Decision {
+	if g.Phase != engine.PhaseGameOver {
+		return nil
+	}
+	for i := range decisions {
+		for _, e := range g.Scores {
+			if e.PlayerID == decisions[i].Player {
+				decisions[i].Score, decisions[i].Rank, decisions[i].Won = e.Total, e.Rank, e.Winner
+			}
+		}
+	}
+	return decisions
+}
+
+// datasetWriter writes each game's decisions as JSON lines, in game order
+// whichever worker finishes first.
+type datasetWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	next    int
+	pending map[int][]Decision
+	err     error
+}
+
+func newDatasetWriter(w io.Writer) *datasetWriter {
+	return &datasetWriter{w: w, pending: make(map[int][]Decision)}
+}
+
+func (dw *datasetWriter) write(game int, decisions []Decision) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.pending[game] = decisions
+	enc := json.NewEncoder(dw.w)
+	for {
+		ds, ok := dw.pending[dw.next]
+		if !ok {
+			return
+		}
+		delete(dw.pending, dw.next)
+		dw.next++
+		for _, d := range ds {
+			if dw.err == nil {
+				dw.err = enc.Encode(d)
+			}
+		}
+	}
+}
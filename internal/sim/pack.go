@@ -0,0 +1,79 @@
+package sim
+
+import (
+	"citadels/internal/engine"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BasePack names the built-in deck in LoadPacks.
+const BasePack = "base"
+
+// PackCard is one line of a card pack file: a JSON array of these.
+//
+//	[{"name": "Tavern", "color": "Trade", "cost": 1, "count": 5}]
+//
+// Colors are spelled as engine.DistrictColor prints them. Purple districts
+// only do something if their name is one the engine knows.
+type PackCard struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Cost  int    `json:"cost"`
+	Count int    `json:"count"`
+}
+
+// LoadPacks builds a deck from card packs: BasePack for the built-in deck,
+// anything else is a pack file.
+func LoadPacks(packs []string) ([]engine.District, error) {
+	var deck []engine.District
+	for _, pack := range packs {
+		if pack == BasePack {
+			deck = append(deck, engine.BaseDistricts()...)
+			continue
+		}
+		cards, err := loadPack(pack)
+		if err != nil {
+			return nil, err
+		}
+		deck = append(deck, cards...)
+	}
+	return deck, nil
+}
+
+func loadPack(path string) ([]engine.District, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pack: %w", err)
+	}
+	var lines []PackCard
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return nil, fmt.Errorf("pack %s: %w", path, err)
+	}
+	var cards []engine.District
+	for _, c := range lines {
+		color := engine.ParseColor(c.Color)
+		if strings.TrimSpace(c.Name) == "" || color == engine.ColorNone || c.Cost < 0 || c.Count <= 0 {
+			return nil, fmt.Errorf("pack %s: invalid card %+v", path, c)
+		}
+		for range c.Count {
+			cards = append(cards, engine.District{Name: c.Name, Color: color, Cost: c.Cost})
+		}
+	}
+	return cards, nil
+}
+
+// ParseRoster turns character names, as engine.CharacterRole prints them,
+// into a roster. An empty list means all eight.
+func ParseRoster(names []string) ([]engine.CharacterRole, error) {
+	var roles []engine.CharacterRole
+	for _, name := range names {
+		r := engine.ParseRole(name)
+		if r == 0 {
+			return nil, fmt.Errorf("%w: unknown character %q", engine.ErrRoster, name)
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
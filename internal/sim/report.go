@@ -0,0 +1,182 @@
+package sim
+
+import (
+	"citadels/internal/engine"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Report is the tally of a simulation. Games stopped without an end are
+// counted in Unfinished and left out of everything else.
+type Report struct {
+	Games      int     `json:"games"`
+	Unfinished int     `json:"unfinished"`
+	AvgRounds  float64 `json:"avg_rounds"`
+	AvgMoves   float64 `json:"avg_moves"`
+
+	Players    []Tally `json:"players"`    // by join order, one per configured bot
+	Seats      []Tally `json:"seats"`      // by seat; seat 1 starts with the crown
+	Characters []Tally `json:"characters"` // players who drafted the character at least once
+	Purples    []Tally `json:"purples"`    // players with the district in their final city
+}
+
+// Tally counts the games some players played and how many they won.
+type Tally struct {
+	Name    string  `json:"name"`
+	Games   int     `json:"games"`
+	Wins    float64 `json:"wins"` // a shared win counts as a fraction
+	WinRate float64 `json:"win_rate"`
+}
+
+func (t *Tally) add(win float64) {
+	t.Games++
+	t.Wins += win
+	t.WinRate = t.Wins / float64(t.Games)
+}
+
+// outcome is what a report needs from one game.
+type outcome struct {
+	finished bool
+	rounds   int
+	moves    int
+	seats    []seat // in seating order
+}
+
+type seat struct {
+	player     int     // join order
+	win        float64 // share of the win
+	characters []engine.CharacterRole
+	purples    []string
+}
+
+func outcomeOf(g *engine.Game) outcome {
+	o := outcome{finished: g.Phase == engine.PhaseGameOver, rounds: g.Round, moves: len(g.Moves())}
+	if !o.finished {
+		return o
+	}
+	winners := 0
+	for _, e := range g.Scores {
+		if e.Winner {
+			winners++
+		}
+	}
+	stats := g.Stats()
+	if stats == nil {
+		stats = &engine.Stats{}
+	}
+	joined := g.JoinOrder()
+	for _, p := range g.Players {
+		s := seat{player: slices.Index(joined, p.ID)}
+		for _, e := range g.Scores {
+			if e.PlayerID == p.ID && e.Winner {
+				s.win = 1 / float64(winners)
+			}
+		}
+		for _, ps := range stats.Players {
+			if ps.PlayerID != p.ID {
+				continue
+			}
+			for _, round := range ps.Drafted {
+				for _, name := range round {
+					if r := engine.ParseRole(name); !slices.Contains(s.characters, r) {
+						s.characters = append(s.characters, r)
+					}
+				}
+			}
+		}
+		for _, d := range p.City {
+			if d.Color == engine.ColorSpecial && !slices.Contains(s.purples, d.Name) {
+				s.purples = append(s.purples, d.Name)
+			}
+		}
+		o.seats = append(o.seats, s)
+	}
+	return o
+}
+
+// newReport sets up an empty tally for every player, seat, character in the
+// roster and purple district in the deck.
+func newReport(o Options) *Report {
+	r := &Report{}
+	for i, d := range o.Bots {
+		r.Players = append(r.Players, Tally{Name: fmt.Sprintf("%s %d", d, i+1)})
+		r.Seats = append(r.Seats, Tally{Name: fmt.Sprintf("seat %d", i+1)})
+	}
+	for _, role := range o.Config.Roster() {
+		r.Characters = append(r.Characters, Tally{Name: role.String()})
+	}
+	var purples []string
+	for _, d := range o.Config.Districts {
+		if d.Color == engine.ColorSpecial && !slices.Contains(purples, d.Name) {
+			purples = append(purples, d.Name)
+		}
+	}
+	slices.Sort(purples)
+	for _, name := range purples {
+		r.Purples = append(r.Purples, Tally{Name: name})
+	}
+	return r
+}
+
+func (r *Report) add(o outcome) {
+	r.Games++
+	if !o.finished {
+		r.Unfinished++
+		return
+	}
+	finished := float64(r.Games - r.Unfinished)
+	r.AvgRounds += (float64(o.rounds) - r.AvgRounds) / finished
+	r.AvgMoves += (float64(o.moves) - r.AvgMoves) / finished
+	for i, s := range o.seats {
+		r.Players[s.player].add(s.win)
+		r.Seats[i].add(s.win)
+		for _, c := range s.characters {
+			if j := slices.IndexFunc(r.Characters, func(t Tally) bool { return t.Name == c.String() }); j >= 0 {
+				r.Characters[j].add(s.win)
+			}
+		}
+		for _, name := range s.purples {
+			if j := slices.IndexFunc(r.Purples, func(t Tally) bool { return t.Name == name }); j >= 0 {
+				r.Purples[j].add(s.win)
+			}
+		}
+	}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the report as one table. Game length rows carry their
+// average in the value column; win rate rows leave it empty.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	f := func(x float64) string { return strconv.FormatFloat(x, 'f', 4, 64) }
+	finished := strconv.Itoa(r.Games - r.Unfinished)
+	cw.Write([]string{"category", "name", "games", "wins", "win_rate", "value"})
+	cw.Write([]string{"games", "unfinished", strconv.Itoa(r.Games), "", "", strconv.Itoa(r.Unfinished)})
+	cw.Write([]string{"length", "rounds", finished, "", "", f(r.AvgRounds)})
+	cw.Write([]string{"length", "moves", finished, "", "", f(r.AvgMoves)})
+	for _, section := range []struct {
+		category string
+		tallies  []Tally
+	}{
+		{"player", r.Players},
+		{"seat", r.Seats},
+		{"character", r.Characters},
+		{"purple", r.Purples},
+	} {
+		for _, t := range section.tallies {
+			cw.Write([]string{section.category, t.Name, strconv.Itoa(t.Games), f(t.Wins), f(t.WinRate), ""})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
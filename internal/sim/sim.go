@@ -0,0 +1,173 @@
+// Package sim plays bot-only games in bulk and tallies who wins them: by
+// seat, by character drafted and by purple district built. It is meant for
+// balancing house rules — a changed deck, end condition or character roster
+// — before trying them at the table.
+package sim
+
+import (
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"citadels/internal/engine/abilities"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// DefaultIterations is the search size of a hard bot in a simulation. Hard
+// bots search a fixed number of iterations rather than for a fixed time, so
+// a simulation gives the same result on any machine.
+const DefaultIterations = 500
+
+// maxSteps stops a game that doesn't end; it is counted as unfinished.
+const maxSteps = 10000
+
+// Options describes a simulation.
+type Options struct {
+	Bots       []bot.Difficulty  // one per player, in join order
+	Games      int               // games to play
+	Seed       uint64            // game i is dealt with Seed+i
+	Workers    int               // games played at once; 0 for one per CPU
+	Iterations int               // search iterations per decision for hard bots; 0 for DefaultIterations
+	Config     engine.GameConfig // deck, end condition and roster; Config.Seed is ignored
+	Dataset    io.Writer         // if set, every bot decision is written to it as a JSON line, see Decision
+}
+
+// Check reports whether the options describe a simulation that can run.
+func (o Options) Check() error {
+	if len(o.Bots) < 2 || len(o.Bots) > 7 {
+		return fmt.Errorf("sim: %d players, want 2 to 7", len(o.Bots))
+	}
+	for _, d := range o.Bots {
+		if _, err := bot.New(d, "", 0); err != nil {
+			return fmt.Errorf("sim: %q: %w", d, err)
+		}
+	}
+	if o.Games <= 0 {
+		return errors.New("sim: no games to play")
+	}
+	if o.Seed == 0 {
+		return errors.New("sim: seed must not be 0")
+	}
+	if len(o.Config.Districts) == 0 || o.Config.EndCitySize <= 0 {
+		return errors.New("sim: incomplete config")
+	}
+	return o.Config.CheckRoster(len(o.Bots))
+}
+
+// Run plays the games and returns the tally. Each game depends only on its
+// seed, so the same options always give the same report.
+func Run(o Options) (*Report, error) {
+	if err := o.Check(); err != nil {
+		return nil, err
+	}
+	workers := o.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var dataset *datasetWriter
+	if o.Dataset != nil {
+		dataset = newDatasetWriter(o.Dataset)
+	}
+	outcomes := make([]outcome, o.Games)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, o.Games) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				g, decisions := play(o, o.Seed+uint64(i), dataset != nil)
+				outcomes[i] = outcomeOf(g)
+				if dataset != nil {
+					dataset.write(i, label(g, decisions))
+				}
+			}
+		}()
+	}
+	for i := range o.Games {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if dataset != nil && dataset.err != nil {
+		return nil, fmt.Errorf("sim: dataset: %w", dataset.err)
+	}
+
+	r := newReport(o)
+	for _, oc := range outcomes {
+		r.add(oc)
+	}
+	return r, nil
+}
+
+// Play plays one game with the given seed until it ends or stalls. Players
+// are named after their bots' difficulty and seat: "normal 1", "hard 2".
+func Play(o Options, seed uint64) *engine.Game {
+	g, _ := play(o, seed, false)
+	return g
+}
+
+// play is Play, also returning every decision if record is set.
+func play(o Options, seed uint64, record bool) (*engine.Game, []Decision) {
+	iterations := o.Iterations
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+	var players []*engine.Player
+	bots := make(map[string]bot.Strategy, len(o.Bots))
+	difficulty := make(map[string]bot.Difficulty, len(o.Bots))
+	for i, d := range o.Bots {
+		id := fmt.Sprintf("p%d", i+1)
+		difficulty[id] = d
+		players = append(players, engine.NewPlayer(id, fmt.Sprintf("%s %d", d, i+1)))
+		s, _ := bot.New(d, id, seed*31+uint64(i))
+		if h, ok := s.(*bot.ISMCTS); ok {
+			h.Budget, h.MaxIterations = 0, iterations
+		}
+		bots[id] = s
+	}
+
+	cfg := o.Config
+	cfg.Seed = seed
+	g := engine.NewGame(players, cfg, abilities.NewRegistry())
+	g.StartGame()
+	var decisions []Decision
+	for step := 0; g.Phase != engine.PhaseGameOver && step < maxSteps; step++ {
+		pid, legal, a := choose(g, bots, seed+uint64(step))
+		if pid == "" {
+			break
+		}
+		if !g.IsLegal(pid, a) {
+			a = legal[0] // a refused choice must not stall the game
+		}
+		if record {
+			decisions = append(decisions, decision(g, pid, difficulty[pid], legal, a))
+		}
+		if _, err := g.Apply(pid, a); err != nil {
+			break
+		}
+	}
+	return g, decisions
+}
+
+// choose asks the first player with a legal action for their choice. It
+// returns "" if nobody can act.
+func choose(g *engine.Game, bots map[string]bot.Strategy, seed uint64) (string, []engine.Action, engine.Action) {
+	for _, p := range g.Players {
+		legal := g.LegalActions(p.ID)
+		if len(legal) == 0 {
+			continue
+		}
+		var a engine.Action
+		if s, ok := bots[p.ID].(bot.Searcher); ok {
+			a = s.Search(g.Determinize(p.ID, seed))
+		} else {
+			a = bots[p.ID].Act(g.ViewFor(p.ID), legal)
+		}
+		return p.ID, legal, a
+	}
+	return "", nil, engine.Action{}
+}
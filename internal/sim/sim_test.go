@@ -0,0 +1,131 @@
+package sim_test
+
+import (
+	"bufio"
+	"bytes"
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"citadels/internal/sim"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	opts := sim.Options{
+		Bots:    []bot.Difficulty{bot.Easy, bot.Normal, bot.Normal, bot.Normal},
+		Games:   30,
+		Seed:    7,
+		Workers: 1,
+		Config:  engine.DefaultConfig(),
+	}
+	r, err := sim.Run(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Games != 30 || r.Unfinished != 0 || r.AvgRounds < 3 {
+		t.Fatalf("got %d games, %d unfinished, %.1f rounds", r.Games, r.Unfinished, r.AvgRounds)
+	}
+	wins := 0.0
+	for _, s := range r.Seats {
+		wins += s.Wins
+	}
+	if math.Abs(wins-30) > 1e-9 {
+		t.Errorf("seats won %v games, want 30", wins)
+	}
+	if len(r.Characters) != 8 || len(r.Purples) != 13 {
+		t.Errorf("%d characters, %d purple districts", len(r.Characters), len(r.Purples))
+	}
+
+	// The tally doesn't depend on how the games are spread over workers
+	opts.Workers = 4
+	again, err := sim.Run(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, again) {
+		t.Error("a different number of workers changed the report")
+	}
+
+	opts.Bots = opts.Bots[:1]
+	if _, err := sim.Run(opts); err == nil {
+		t.Error("one player: want an error")
+	}
+	opts.Bots = []bot.Difficulty{bot.Normal, bot.Normal, bot.Normal, bot.Normal, bot.Normal, bot.Normal}
+	opts.Config.Roles, _ = sim.ParseRoster([]string{"Assassin", "Thief", "Magician", "King", "Bishop", "Merchant"})
+	if _, err := sim.Run(opts); !errors.Is(err, engine.ErrRoster) {
+		t.Errorf("6 players, 6 characters: got %v, want ErrRoster", err)
+	}
+}
+
+func TestDataset(t *testing.T) {
+	opts := sim.Options{
+		Bots:    []bot.Difficulty{bot.Easy, bot.Normal, bot.Normal},
+		Games:   4,
+		Seed:    11,
+		Workers: 3,
+		Config:  engine.DefaultConfig(),
+	}
+	var buf bytes.Buffer
+	opts.Dataset = &buf
+	r, err := sim.Run(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var game uint64
+	wins := map[uint64]bool{}
+	lines := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	lines.Buffer(nil, 1<<20)
+	for lines.Scan() {
+		var d sim.Decision
+		if err := json.Unmarshal(lines.Bytes(), &d); err != nil {
+			t.Fatal(err)
+		}
+		if d.Game < game {
+			t.Fatalf("game %d written after game %d", d.Game, game)
+		}
+		game = d.Game
+		if len(d.Features) != len(sim.FeatureNames) {
+			t.Fatalf("%d features, %d names", len(d.Features), len(sim.FeatureNames))
+		}
+		if d.Chosen < 0 || d.Chosen >= len(d.Legal) || d.Legal[d.Chosen].Action.Type != d.Action.Type {
+			t.Fatalf("game %d step %d: chosen %d of %d legal", d.Game, d.Step, d.Chosen, len(d.Legal))
+		}
+		if d.Rank == 0 || d.Won && d.Rank != 1 {
+			t.Fatalf("game %d step %d: unlabelled", d.Game, d.Step)
+		}
+		if d.Won {
+			wins[d.Game] = true
+		}
+	}
+	if len(wins) != r.Games-r.Unfinished {
+		t.Errorf("winners in %d games, want %d", len(wins), r.Games-r.Unfinished)
+	}
+}
+
+func TestLoadPacks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "house.json")
+	os.WriteFile(path, []byte(`[{"name": "Guildhall", "color": "Trade", "cost": 4, "count": 2}]`), 0o644)
+
+	deck, err := sim.LoadPacks([]string{sim.BasePack, path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := len(engine.BaseDistricts())
+	if len(deck) != base+2 || deck[base].Name != "Guildhall" || deck[base].Color != engine.ColorTrade {
+		t.Errorf("got %d cards, last %+v", len(deck), deck[len(deck)-1])
+	}
+
+	os.WriteFile(path, []byte(`[{"name": "Guildhall", "color": "Teal", "cost": 4, "count": 2}]`), 0o644)
+	if _, err := sim.LoadPacks([]string{path}); err == nil {
+		t.Error("unknown color: want an error")
+	}
+	if _, err := sim.ParseRoster([]string{"Queen"}); !errors.Is(err, engine.ErrRoster) {
+		t.Errorf("unknown character: got %v", err)
+	}
+}
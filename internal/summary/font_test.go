@@ -0,0 +1,29 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlyphs(t *testing.T) {
+	for r, g := range glyphs {
+		for _, row := range g {
+			if len(row) != glyphW || strings.Trim(row, ".#") != "" {
+				t.Errorf("glyph %q: bad row %q", r, row)
+			}
+		}
+	}
+
+	// The UI defaults to Russian, so names are often Cyrillic
+	for _, s := range []string{
+		"АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ",
+		"абвгдеёжзийклмнопрстуфхцчшщъыьэюя",
+		"Дмитрий",
+	} {
+		for _, r := range s {
+			if glyph(r) == glyphs['?'] {
+				t.Errorf("%q is drawn as '?'", r)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package summary renders the shareable end-of-game summary card as a PNG:
+// the winner, the date, the score breakdown and every final city as colored
+// tiles. It draws with the standard library only, using its own bitmap font.
+package summary
+
+import (
+	"bytes"
+	"citadels/internal/archive"
+	"citadels/internal/engine"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+)
+
+const (
+	width   = 900
+	margin  = 30
+	rowGap  = 12
+	tileW   = 48
+	tileH   = 30
+	tileGap = 6
+	maxName = 16
+)
+
+var (
+	background = color.RGBA{0x1a, 0x1a, 0x2e, 0xff}
+	gold       = color.RGBA{0xff, 0xd7, 0x00, 0xff}
+	text       = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	muted      = color.RGBA{0x88, 0x88, 0x99, 0xff}
+	rule       = color.RGBA{0x33, 0x33, 0x4d, 0xff}
+
+	// districtColors match .color-* in common.css.
+	districtColors = map[engine.DistrictColor]color.RGBA{
+		engine.ColorNoble:     {0xc9, 0xa2, 0x27, 0xff},
+		engine.ColorReligious: {0x4a, 0x90, 0xd9, 0xff},
+		engine.ColorTrade:     {0x45, 0xa0, 0x49, 0xff},
+		engine.ColorMilitary:  {0xd9, 0x53, 0x4f, 0xff},
+		engine.ColorSpecial:   {0x9b, 0x59, 0xb6, 0xff},
+	}
+)
+
+// columns of the score table: header and value.
+var columns = []struct {
+	title string
+	x     int
+	value func(engine.ScoreEntry) int
+}{
+	{"DISTRICTS", 330, func(e engine.ScoreEntry) int { return e.DistrictScore }},
+	{"COLORS", 460, func(e engine.ScoreEntry) int { return e.ColorBonus }},
+	{"COMPLETE", 565, func(e engine.ScoreEntry) int { return e.FirstComplete + e.OtherComplete }},
+	{"SPECIAL", 685, func(e engine.ScoreEntry) int { return e.SpecialBonus }},
+	{"TOTAL", 800, func(e engine.ScoreEntry) int { return e.Total }},
+}
+
+// Render draws the summary card of a finished game.
+func Render(r archive.Result) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height(r)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	y := margin
+	drawText(img, margin, y, 4, gold, "CITADELS")
+	date := r.Finished.Format("2006-01-02")
+	drawText(img, width-margin-textWidth(date, 2), y+14, 2, muted, date)
+	y += glyphH*4 + rowGap*2
+
+	drawText(img, margin, y, 3, gold, winnerLine(r))
+	y += glyphH*3 + rowGap*2
+
+	drawText(img, margin, y, 2, muted, "PLAYER")
+	for _, c := range columns {
+		drawText(img, c.x, y, 2, muted, c.title)
+	}
+	y += glyphH*2 + rowGap
+	fill(img, margin, y, width-2*margin, 2, rule)
+	y += rowGap
+
+	for _, e := range r.Scores {
+		name := fmt.Sprintf("%d. %s", e.Rank, clip(e.PlayerName))
+		c := text
+		if e.Winner {
+			c = gold
+		}
+		drawText(img, margin, y, 2, c, name)
+		for _, col := range columns {
+			drawText(img, col.x, y, 2, c, fmt.Sprint(col.value(e)))
+		}
+		y += glyphH*2 + rowGap
+
+		x := margin
+		for _, d := range r.Cities[e.PlayerID] {
+			if x+tileW > width-margin {
+				x = margin
+				y += tileH + tileGap
+			}
+			drawTile(img, x, y, d)
+			x += tileW + tileGap
+		}
+		y += tileH + rowGap*2
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// height lays the card out without drawing, so the image can be sized first.
+func height(r archive.Result) int {
+	h := margin + glyphH*4 + rowGap*2 + glyphH*3 + rowGap*2 + glyphH*2 + rowGap + 2 + rowGap
+	perRow := (width - 2*margin + tileGap) / (tileW + tileGap)
+	for _, e := range r.Scores {
+		rows := max(1, (len(r.Cities[e.PlayerID])+perRow-1)/perRow)
+		h += glyphH*2 + rowGap + rows*(tileH+tileGap) - tileGap + rowGap*2
+	}
+	return h + margin
+}
+
+func winnerLine(r archive.Result) string {
+	var names []string
+	for _, e := range r.Scores {
+		if e.Winner {
+			names = append(names, clip(e.PlayerName))
+		}
+	}
+	if len(names) == 1 {
+		return "WINNER: " + names[0]
+	}
+	return "WINNERS: " + strings.Join(names, ", ")
+}
+
+func clip(name string) string {
+	if r := []rune(name); len(r) > maxName {
+		return string(r[:maxName-1]) + "."
+	}
+	return name
+}
+
+// drawTile draws one district as a tile in its color with its cost.
+func drawTile(img *image.RGBA, x, y int, d engine.District) {
+	c, ok := districtColors[d.Color]
+	if !ok {
+		c = muted
+	}
+	fill(img, x, y, tileW, tileH, c)
+	cost := fmt.Sprint(d.Cost)
+	drawText(img, x+(tileW-textWidth(cost, 2))/2, y+(tileH-glyphH*2)/2, 2, background, cost)
+}
+
+func fill(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// textWidth is the width of s drawn at the given scale.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphW+1) - 1) * scale
+}
+
+// drawText draws s with its top-left corner at x, y, each font pixel
+// scale x scale pixels.
+func drawText(img *image.RGBA, x, y, scale int, c color.RGBA, s string) {
+	for _, r := range s {
+		for row, bits := range glyph(r) {
+			for col, bit := range bits {
+				if bit == '#' {
+					fill(img, x+col*scale, y+row*scale, scale, scale, c)
+				}
+			}
+		}
+		x += (glyphW + 1) * scale
+	}
+}
@@ -0,0 +1,37 @@
+package summary_test
+
+import (
+	"bytes"
+	"citadels/internal/archive"
+	"citadels/internal/engine"
+	"citadels/internal/summary"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	res := archive.Result{
+		GameID:   "g1",
+		Finished: time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC),
+		Scores: []engine.ScoreEntry{
+			{PlayerID: "A", PlayerName: "Дмитрий", Total: 12, Rank: 1, Winner: true},
+			{PlayerID: "B", PlayerName: "Bob", Total: 9, Rank: 2},
+		},
+		Winners: []string{"A"},
+		Cities: map[string][]engine.District{
+			"A": {{ID: 1, Name: "Castle", Cost: 4, Color: engine.ColorNoble}},
+		},
+	}
+	data, err := summary.Render(res)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 900 || b.Dy() < 200 {
+		t.Errorf("size %dx%d", b.Dx(), b.Dy())
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"embed"
 	"flag"
 	"log"
+	"os"
+	"slices"
 
+	"citadels/internal/archive"
+	"citadels/internal/bot"
 	"citadels/internal/server"
 )
 
@@ -12,10 +16,29 @@ import (
 var static embed.FS
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		if err := simulate(os.Args[2:]); err != nil {
+			log.Fatalf("simulate: %v", err)
+		}
+		return
+	}
+
 	port := flag.Int("port", 80, "server port")
+	archiveDir := flag.String("archive", "", "directory to keep finished game results in (default: memory only)")
+	botBudget := flag.Duration("bot-budget", bot.DefaultBudget, "search time per decision for hard bots")
+	autopilot := flag.String("autopilot", string(bot.Normal), "bot difficulty the autopilot plays absent players with")
 	flag.Parse()
 
-	srv := server.New(*port, static)
+	if !slices.Contains(bot.Difficulties, bot.Difficulty(*autopilot)) {
+		log.Fatalf("autopilot: %v: %q", bot.ErrDifficulty, *autopilot)
+	}
+
+	arch, err := archive.New(*archiveDir)
+	if err != nil {
+		log.Fatalf("archive: %v", err)
+	}
+
+	srv := server.New(*port, static, arch, *botBudget, bot.Difficulty(*autopilot))
 	if err := srv.Start(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"citadels/internal/bot"
+	"citadels/internal/engine"
+	"citadels/internal/sim"
+)
+
+// simulate runs the simulate subcommand: bot-only games in bulk, with the
+// tally written as CSV or JSON.
+func simulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	games := fs.Int("games", 1000, "number of games to play")
+	bots := fs.String("bots", "normal,normal,normal,normal", "comma-separated bot difficulty per player: easy, normal or hard")
+	seed := fs.Uint64("seed", 1, "seed of the first game; game i uses seed+i")
+	workers := fs.Int("workers", 0, "games played in parallel (default: one per CPU)")
+	iterations := fs.Int("iterations", sim.DefaultIterations, "search iterations per decision for hard bots")
+	endCity := fs.Int("end-city", engine.DefaultConfig().EndCitySize, "districts that trigger the end of the game")
+	packs := fs.String("packs", sim.BasePack, "comma-separated card packs: base or a JSON pack file")
+	roster := fs.String("roster", "", "comma-separated characters to deal, e.g. Assassin,Thief,... (default: all eight)")
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("out", "", "file to write the report to (default: stdout)")
+	dataset := fs.String("dataset", "", "file to write every bot decision to as JSON lines, for training")
+	fs.Parse(args)
+
+	opts := sim.Options{
+		Games:      *games,
+		Seed:       *seed,
+		Workers:    *workers,
+		Iterations: *iterations,
+		Config:     engine.GameConfig{EndCitySize: *endCity},
+	}
+	for _, d := range split(*bots) {
+		opts.Bots = append(opts.Bots, bot.Difficulty(d))
+	}
+	var err error
+	if opts.Config.Districts, err = sim.LoadPacks(split(*packs)); err != nil {
+		return err
+	}
+	if opts.Config.Roles, err = sim.ParseRoster(split(*roster)); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	var data *os.File
+	var buf *bufio.Writer
+	if *dataset != "" {
+		if data, err = os.Create(*dataset); err != nil {
+			return err
+		}
+		buf = bufio.NewWriter(data)
+		opts.Dataset = buf
+	}
+
+	report, err := sim.Run(opts)
+	if data != nil {
+		// A failed write must not leave a truncated dataset behind a
+		// successful exit
+		if ferr := errors.Join(buf.Flush(), data.Close()); ferr != nil && err == nil {
+			err = fmt.Errorf("dataset: %w", ferr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		return report.WriteJSON(w)
+	}
+	return report.WriteCSV(w)
+}
+
+// split splits a comma-separated flag, dropping blanks.
+func split(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
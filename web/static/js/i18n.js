@@ -89,6 +89,13 @@
             'link_copied': 'Copied!',
             'game_label': 'Game',
             'players_joined': 'player(s) joined',
+            'add_bot_easy': '+ Easy bot',
+            'add_bot_normal': '+ Normal bot',
+            'add_bot_hard': '+ Hard bot',
+            'bot_easy': 'easy',
+            'bot_normal': 'normal',
+            'bot_hard': 'hard',
+            'remove_bot': 'Remove bot',
             'waiting_for_players': 'Waiting for players...',
             'ready': 'Ready!',
             'not_ready': 'Not Ready',
@@ -121,6 +128,7 @@
             'city': 'Your City',
             'waiting_playing': 'is playing',
             'waiting': 'Waiting...',
+            'waiting_decision': 'Waiting for another player to decide...',
             'choose_card_keep': 'Choose a card to keep',
             'swap_hand': 'Swap hand',
             'discard_draw': 'Discard & Draw',
@@ -148,9 +156,125 @@
             'total': 'Total',
             'pts': 'pts',
             'exit_game': 'Exit',
+            'save_summary': 'Save image',
+            'export_record': 'Download game record',
+            'watch_replay': 'Watch replay',
+            'replay': 'Replay',
+            'replay_unavailable': 'Replay unavailable',
+            'replay_prev_round': 'Previous round',
+            'replay_next_round': 'Next round',
+            'replay_back': 'Step back',
+            'replay_forward': 'Step forward',
+            'replay_reveal': 'Show hands',
+            'undo_btn': 'Undo last action',
+            'undo_waiting': 'Asked the table to undo: {action}. Waiting for approval…',
+            'undo_asked': '{player} asks to undo: {action}',
+            'undo_approve': 'Allow',
+            'undo_reject': 'Refuse',
+            'undo_vote_on_phones': 'Players vote on their phones',
+            'undo_act_take_gold': 'take 2 gold',
+            'undo_act_build': 'build {district}',
+            'undo_act_ability': 'character ability',
+            'undo_act_end_turn': 'end turn',
+            'undo_act_keep_card': 'card choice',
+            'undo_act_collect_gold': 'collect gold',
+            'undo_act_graveyard_respond': 'Graveyard choice',
+            'ev_undone': '{player} took back: {action}',
+            'ev_undo_rejected': "{by} refused {player}'s undo",
+            'ev_undo_cancelled': "{player}'s undo request lapsed",
+            'autopilot': 'autopilot',
+            'autopilot_offer': 'Autopilot for my next {turns} turns',
+            'autopilot_on_timeout': 'Time ran out, so the autopilot is playing for you.',
+            'autopilot_on_disconnected': 'The autopilot is playing for you while you are away.',
+            'autopilot_on_requested': 'The autopilot is playing your turns: {turns} left.',
+            'autopilot_take_over': 'Take back control',
+            'autopilot_reason_timeout': 'time ran out',
+            'autopilot_reason_disconnected': 'disconnected',
+            'autopilot_reason_requested': 'asked for the autopilot',
+            'setting_hints': 'Allow hints',
+            'setting_rated': 'Rated game (no hints)',
+            'premove_draft': 'Pick for me when my turn comes — tap in order of preference:',
+            'premove_pass': 'Next turn: take gold and end it',
+            'hint_btn': 'Hint: what should I do?',
+            'hint_title': 'Suggestions',
+            'hint_none': 'Nothing to suggest right now.',
+            'hint_play': 'Do it',
+            'hint_act_draft_pick': 'Pick the {character}',
+            'hint_act_keep_card': 'Keep {district}',
+            'hint_act_build': 'Build {district}',
+            'hint_act_lab_discard': 'Laboratory: discard {district}',
+            'hint_act_graveyard_accept': 'Pay 1 gold to keep the district',
+            'hint_act_graveyard_decline': 'Let the district go',
+            'hint_act_swap': 'Swap hands with {player}',
+            'hint_act_discard': 'Discard {cards} cards and draw as many',
+            'hint_act_destroy': "Destroy {player}'s {district}",
+            'hint_act_target': 'Target the {character}',
+            'hint_act_take_gold': 'Take 2 gold',
+            'hint_act_draw_cards': 'Draw cards',
+            'hint_act_collect_gold': 'Collect gold from your districts',
+            'hint_act_smithy_draw': 'Smithy: pay 2 gold for 3 cards',
+            'hint_act_end_turn': 'End your turn',
+            'hint_collect_gold': 'Free income: {gold} gold',
+            'hint_draft_assassin': 'The Assassin can stop the strongest character this round',
+            'hint_draft_thief': 'The Thief can steal a rich player\'s gold',
+            'hint_draft_thief_poor': 'You are short of gold; the Thief takes someone else\'s',
+            'hint_draft_magician': 'The Magician can swap hands or redraw cards',
+            'hint_draft_magician_empty': 'Your hand is empty; the Magician can swap it for a full one',
+            'hint_draft_magician_stuck': 'You can\'t afford anything in hand; the Magician can change that',
+            'hint_draft_king': 'Takes the crown and pays {count} for your noble districts',
+            'hint_draft_bishop': 'Pays {count} for your religious districts',
+            'hint_draft_bishop_protect': 'Your city is big; the Bishop keeps it safe from the Warlord',
+            'hint_draft_merchant': 'Pays {count} for your trade districts, plus 1 gold',
+            'hint_draft_architect': 'The Architect draws 2 more cards and builds up to 3',
+            'hint_draft_architect_build': 'You have the gold and the cards to build up to 3 districts',
+            'hint_draft_architect_draw': 'Your hand is thin; the Architect draws 2 more cards',
+            'hint_draft_warlord': 'Pays {count} for your military districts',
+            'hint_draft_warlord_leader': 'An opponent has {count} districts; the Warlord can slow them down',
+            'hint_keep': '{district} costs {cost} and is within reach',
+            'hint_keep_expensive': '{district} costs {cost}, more than you can pay soon',
+            'hint_keep_duplicate': 'You already have {district}',
+            'hint_build': '{district} is worth {cost} points',
+            'hint_build_new_color': '{district} adds a {color} district to your city',
+            'hint_build_best': '{district} is your highest-value affordable build',
+            'hint_draw': 'New cards give you more choice',
+            'hint_draw_nothing_to_build': 'Nothing in your hand is worth building',
+            'hint_draw_hand_thin': 'You can already pay for your hand; find more to build',
+            'hint_gold': 'Gold lets you build what you hold',
+            'hint_gold_save': 'Your hand is good; save up for {district}',
+            'hint_assassin_target': 'The {character} is usually one of the strongest this round',
+            'hint_thief_target': 'The {character} usually has the most gold',
+            'hint_own_character': 'The {character} is yours',
+            'hint_magician_swap': '{player} holds {cards} cards, more than you',
+            'hint_magician_swap_small': '{player} has only {cards} cards',
+            'hint_magician_discard': 'Swap {cards} cards you can\'t use for new ones',
+            'hint_magician_discard_none': 'Every card in your hand is useful',
+            'hint_warlord_threat': '{player} has {count} districts and is close to ending the game',
+            'hint_warlord_free': 'Destroying {district} costs you nothing',
+            'hint_warlord_costly': 'Destroying {district} costs {cost} gold',
+            'hint_lab': 'Trade {district} for 1 gold',
+            'hint_lab_duplicate': 'You have already built {district}; trade it for 1 gold',
+            'hint_smithy': 'More cards, but gold is better spent on building',
+            'hint_smithy_hand_thin': 'Your hand is nearly empty and you have gold to spare',
+            'hint_graveyard_keep': 'Keep {district} for just 1 gold',
+            'hint_graveyard_decline': 'Save your gold',
+            'hint_end_turn': 'Nothing else is worth doing this turn',
+
+            // UI — game statistics
+            'stats_title': 'Statistics',
+            'stat_gold_earned': 'Gold earned',
+            'stat_gold_stolen': 'Stolen',
+            'stat_gold_lost': 'Lost to Thief',
+            'stat_cards_drawn': 'Cards drawn',
+            'stat_built': 'Built',
+            'stat_destroyed': 'Destroyed',
+            'stat_districts_lost': 'Lost districts',
+            'stat_murdered': 'Murdered',
+            'stat_robbed': 'Robbed',
+            'stat_turns': 'Turns',
 
             // UI — table panel
             'table_title': 'Other Players',
+            'could_be': 'Could be: {roles}',
 
             // UI — collect gold
             'collect_gold': 'Collect Gold ({count})',
@@ -175,6 +299,7 @@
             'ev_merchant_bonus': '{player} (Merchant) +1 bonus gold',
             'ev_architect_draw': '{player} (Architect) drew {count} extra cards',
             'ev_warlord_destroy': '{player} (Warlord) destroyed {district} of {target} for {cost}g',
+            'confirm_destroy': 'Destroy {district}? It will cost {cost} gold, leaving you {gold}.',
             'ev_lab_discard': '{player}: Laboratory — discarded {district} for 2g',
             'ev_smithy_draw': '{player}: Smithy — 2g → {count} cards',
             'ev_graveyard_accept': '{player}: Graveyard — paid 1g for {district}',
@@ -299,6 +424,13 @@
             'link_copied': 'Скопировано!',
             'game_label': 'Игра',
             'players_joined': 'игрок(ов) в игре',
+            'add_bot_easy': '+ Простой бот',
+            'add_bot_normal': '+ Обычный бот',
+            'add_bot_hard': '+ Сильный бот',
+            'bot_easy': 'простой',
+            'bot_normal': 'обычный',
+            'bot_hard': 'сильный',
+            'remove_bot': 'Убрать бота',
             'waiting_for_players': 'Ожидание игроков...',
             'ready': 'Готов!',
             'not_ready': 'Не готов',
@@ -331,6 +463,7 @@
             'city': 'Ваш город',
             'waiting_playing': 'играет',
             'waiting': 'Ожидание...',
+            'waiting_decision': 'Ожидание решения другого игрока...',
             'choose_card_keep': 'Выберите карту',
             'swap_hand': 'Обменяться рукой',
             'discard_draw': 'Сбросить и взять',
@@ -358,9 +491,125 @@
             'total': 'Итого',
             'pts': 'очк.',
             'exit_game': 'Выйти',
+            'save_summary': 'Сохранить картинку',
+            'export_record': 'Скачать запись партии',
+            'watch_replay': 'Смотреть повтор',
+            'replay': 'Повтор',
+            'replay_unavailable': 'Повтор недоступен',
+            'replay_prev_round': 'Предыдущий раунд',
+            'replay_next_round': 'Следующий раунд',
+            'replay_back': 'Шаг назад',
+            'replay_forward': 'Шаг вперёд',
+            'replay_reveal': 'Показать руки',
+            'undo_btn': 'Отменить последнее действие',
+            'undo_waiting': 'Запрошена отмена: {action}. Ждём одобрения…',
+            'undo_asked': '{player} просит отменить: {action}',
+            'undo_approve': 'Разрешить',
+            'undo_reject': 'Отказать',
+            'undo_vote_on_phones': 'Игроки голосуют на телефонах',
+            'undo_act_take_gold': 'взять 2 золота',
+            'undo_act_build': 'построить {district}',
+            'undo_act_ability': 'способность персонажа',
+            'undo_act_end_turn': 'конец хода',
+            'undo_act_keep_card': 'выбор карты',
+            'undo_act_collect_gold': 'сбор золота',
+            'undo_act_graveyard_respond': 'выбор Кладбища',
+            'ev_undone': '{player} отменяет: {action}',
+            'ev_undo_rejected': '{by} отказывает {player} в отмене',
+            'ev_undo_cancelled': 'Запрос {player} на отмену снят',
+            'autopilot': 'автопилот',
+            'autopilot_offer': 'Автопилот на {turns} хода',
+            'autopilot_on_timeout': 'Время вышло, за вас играет автопилот.',
+            'autopilot_on_disconnected': 'Пока вас нет, за вас играет автопилот.',
+            'autopilot_on_requested': 'За вас играет автопилот: осталось ходов — {turns}.',
+            'autopilot_take_over': 'Вернуть управление',
+            'autopilot_reason_timeout': 'время вышло',
+            'autopilot_reason_disconnected': 'нет связи',
+            'autopilot_reason_requested': 'включил автопилот',
+            'setting_hints': 'Разрешить подсказки',
+            'setting_rated': 'Рейтинговая игра (без подсказок)',
+            'premove_draft': 'Выбрать за меня, когда дойдёт очередь, — отметьте по порядку:',
+            'premove_pass': 'Следующий ход: взять золото и закончить',
+            'hint_btn': 'Подсказка: что делать?',
+            'hint_title': 'Советы',
+            'hint_none': 'Сейчас советовать нечего.',
+            'hint_play': 'Сделать',
+            'hint_act_draft_pick': 'Взять {character}',
+            'hint_act_keep_card': 'Оставить {district}',
+            'hint_act_build': 'Построить {district}',
+            'hint_act_lab_discard': 'Лаборатория: сбросить {district}',
+            'hint_act_graveyard_accept': 'Заплатить 1 золото и оставить квартал',
+            'hint_act_graveyard_decline': 'Отпустить квартал',
+            'hint_act_swap': 'Обменяться рукой с {player}',
+            'hint_act_discard': 'Сбросить {cards} карт и взять столько же',
+            'hint_act_destroy': 'Разрушить {district} у {player}',
+            'hint_act_target': 'Цель: {character}',
+            'hint_act_take_gold': 'Взять 2 золота',
+            'hint_act_draw_cards': 'Тянуть карты',
+            'hint_act_collect_gold': 'Собрать доход с кварталов',
+            'hint_act_smithy_draw': 'Кузница: 2 золота за 3 карты',
+            'hint_act_end_turn': 'Закончить ход',
+            'hint_collect_gold': 'Бесплатный доход: {gold} золота',
+            'hint_draft_assassin': 'Убийца может остановить сильнейшего персонажа раунда',
+            'hint_draft_thief': 'Вор может украсть золото у богатого игрока',
+            'hint_draft_thief_poor': 'У вас мало золота; Вор возьмёт чужое',
+            'hint_draft_magician': 'Чародей может обменять руку или карты',
+            'hint_draft_magician_empty': 'Рука пуста; Чародей обменяет её на полную',
+            'hint_draft_magician_stuck': 'Ничего в руке вам не по карману; Чародей это исправит',
+            'hint_draft_king': 'Корона и {count} золота за дворянские кварталы',
+            'hint_draft_bishop': '{count} золота за церковные кварталы',
+            'hint_draft_bishop_protect': 'Город большой; Епископ защитит его от Кондотьера',
+            'hint_draft_merchant': '{count} золота за торговые кварталы и ещё 1',
+            'hint_draft_architect': 'Зодчий берёт 2 лишние карты и строит до 3 кварталов',
+            'hint_draft_architect_build': 'Хватает золота и карт, чтобы построить до 3 кварталов',
+            'hint_draft_architect_draw': 'Карт мало; Зодчий возьмёт ещё 2',
+            'hint_draft_warlord': '{count} золота за военные кварталы',
+            'hint_draft_warlord_leader': 'У соперника {count} кварталов; Кондотьер его притормозит',
+            'hint_keep': '{district} стоит {cost} и скоро будет по карману',
+            'hint_keep_expensive': '{district} стоит {cost}, столько вы скоро не соберёте',
+            'hint_keep_duplicate': '{district} у вас уже есть',
+            'hint_build': '{district} приносит {cost} очков',
+            'hint_build_new_color': '{district} добавит в город новый цвет: {color}',
+            'hint_build_best': '{district} — самая ценная постройка, которая вам по карману',
+            'hint_draw': 'Новые карты дадут больше выбора',
+            'hint_draw_nothing_to_build': 'В руке нет ничего, что стоит строить',
+            'hint_draw_hand_thin': 'На руку золота хватает; найдите, что ещё строить',
+            'hint_gold': 'Золото нужно, чтобы строить то, что в руке',
+            'hint_gold_save': 'Рука хорошая; копите на {district}',
+            'hint_assassin_target': '{character} обычно один из сильнейших в раунде',
+            'hint_thief_target': 'У персонажа {character} обычно больше всего золота',
+            'hint_own_character': '{character} — ваш персонаж',
+            'hint_magician_swap': 'У {player} {cards} карт, больше чем у вас',
+            'hint_magician_swap_small': 'У {player} всего {cards} карт',
+            'hint_magician_discard': 'Замените {cards} ненужных карт на новые',
+            'hint_magician_discard_none': 'Все карты в руке пригодятся',
+            'hint_warlord_threat': 'У {player} {count} кварталов, игра близка к концу',
+            'hint_warlord_free': 'Разрушить {district} ничего не стоит',
+            'hint_warlord_costly': 'Разрушение {district} стоит {cost} золота',
+            'hint_lab': 'Обменять {district} на 1 золото',
+            'hint_lab_duplicate': '{district} уже построен; обменяйте карту на 1 золото',
+            'hint_smithy': 'Больше карт, но золото лучше потратить на стройку',
+            'hint_smithy_hand_thin': 'Рука почти пуста, а золото есть',
+            'hint_graveyard_keep': 'Оставить {district} всего за 1 золото',
+            'hint_graveyard_decline': 'Сберегите золото',
+            'hint_end_turn': 'Больше в этом ходу делать нечего',
+
+            // UI — game statistics
+            'stats_title': 'Статистика',
+            'stat_gold_earned': 'Заработано',
+            'stat_gold_stolen': 'Украдено',
+            'stat_gold_lost': 'Отдано Вору',
+            'stat_cards_drawn': 'Взято карт',
+            'stat_built': 'Построено',
+            'stat_destroyed': 'Разрушено',
+            'stat_districts_lost': 'Потеряно районов',
+            'stat_murdered': 'Убит',
+            'stat_robbed': 'Ограблен',
+            'stat_turns': 'Ходов',
 
             // UI — table panel
             'table_title': 'Другие игроки за столом',
+            'could_be': 'Может быть: {roles}',
 
             // UI — collect gold
             'collect_gold': 'Собрать золото ({count})',
@@ -385,6 +634,7 @@
             'ev_merchant_bonus': '{player} (Купец) +1 бонусное золото',
             'ev_architect_draw': '{player} (Зодчий) взял {count} доп. карт',
             'ev_warlord_destroy': '{player} (Кондотьер) разрушил {district} у {target} за {cost}з',
+            'confirm_destroy': 'Разрушить {district}? Это стоит {cost} з, у вас останется {gold}.',
             'ev_lab_discard': '{player}: Лаборатория — сбросил {district} за 2з',
             'ev_smithy_draw': '{player}: Кузня — 2з → {count} карт',
             'ev_graveyard_accept': '{player}: Кладбище — выкупил {district} за 1з',
@@ -491,7 +741,7 @@
 
     window.characterBarHTML = function(state) {
         var phase = state.phase || '';
-        if (phase === 'Lobby' || phase === 'DraftSetup' || phase === 'DraftPick' || phase === 'GameOver') return '';
+        if (phase === 'Lobby' || phase === 'DraftPick' || phase === 'GameOver') return '';
         var callNum = state.current_call_num || 0;
         var murdered = state.murdered_role || '';
         var robbed = state.robbed_role || '';
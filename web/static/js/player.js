@@ -11,6 +11,12 @@
     let magicianMode = null; // 'swap_hand' | 'discard_draw' | null
     let selectedDiscardIndices = new Set();
     let labMode = false;
+    let undoRequest = null; // open undo_requested, see undoHTML
+    let undoVoted = false;
+    let autopilot = []; // autopilot_update players, see autopilotHTML
+    const AUTOPILOT_TURNS = 3;
+    let hints = null; // hints reply for the current state, see hintHTML
+    let premoves = { draft: [], pass: false }; // what I queued, see premoveHTML
     const logKey = 'citadels_log_' + gameID;
     const eventLog = JSON.parse(sessionStorage.getItem(logKey) || '[]');
     const MAX_LOG = 30;
@@ -27,14 +33,39 @@
         localStorage.setItem('citadels_player_id', playerID);
     }
 
+    // projectedScore is the running total from the server's projection,
+    // which includes own hidden bonuses such as the Map Room.
+    function projectedScore(p) {
+        const proj = (state.projection || []).find(s => s.player_id === p.id);
+        return proj ? proj.total : (p.city || []).reduce((sum, d) => sum + d.cost, 0);
+    }
+
+    // confirmPreview asks the player to confirm a previewed action, showing
+    // what it would cost, and sends it if they agree.
+    function confirmPreview(p) {
+        if (p.error) { showError(p.error); return; }
+        const used = (p.events || []).find(ev => ev.type === 'ability_used' && ev.data.ability === 'warlord');
+        if (used && !confirm(t('confirm_destroy', { district: t(used.data.district), cost: used.data.cost || 0, gold: p.gold }))) {
+            return;
+        }
+        const { type, ...payload } = p.action;
+        ws.send(type, payload);
+    }
+
     function connectWS() {
         const wsUrl = `ws://${location.host}/ws?game=${gameID}&player=${playerID}&type=player`;
         ws = new WS(wsUrl,
             (env) => {
                 if (env.type === 'lobby_update') { lobbyState = env.payload; render(); }
-                else if (env.type === 'player_state') { state = env.payload; render(); }
+                else if (env.type === 'player_state') { state = env.payload; hints = null; render(); }
                 else if (env.type === 'error') showError(env.payload.message);
                 else if (env.type === 'event') { pushEvent(env.payload); render(); }
+                else if (env.type === 'preview_result') confirmPreview(env.payload);
+                else if (env.type === 'undo_requested') { undoRequest = env.payload; undoVoted = false; render(); }
+                else if (env.type === 'undo_result') { undoRequest = null; pushEntry(undoResultEntry(env.payload)); render(); }
+                else if (env.type === 'autopilot_update') { autopilot = env.payload.players || []; render(); }
+                else if (env.type === 'hints') { hints = env.payload.hints || []; render(); }
+                else if (env.type === 'premoves') { premoves = env.payload; render(); }
             },
             () => { if (joined) rejoin(); },
             () => {}
@@ -84,8 +115,13 @@
                 ${langSwitcherHTML()}
                 <h2>${t('waiting_for_players')}</h2>
                 <div class="player-lobby-players">
-                    ${players.map(p => `<div class="player-lobby-player ${p.ready ? 'ready' : ''}">${p.name} ${p.ready ? '✓' : '...'}</div>`).join('')}
+                    ${players.map(p => p.bot
+                        ? `<div class="player-lobby-player ready bot">🤖 ${p.name} <span class="bot-level">${t('bot_' + p.bot)}</span>
+                            <button class="bot-remove" data-remove-bot="${p.id}" title="${t('remove_bot')}">✕</button></div>`
+                        : `<div class="player-lobby-player ${p.ready ? 'ready' : ''}">${p.name} ${p.ready ? '✓' : '...'}</div>`).join('')}
                 </div>
+                ${players.length < 7 ? `<div class="player-lobby-bots">${['easy', 'normal', 'hard'].map(d =>
+                    `<button class="btn-secondary" data-add-bot="${d}">${t('add_bot_' + d)}</button>`).join('')}</div>` : ''}
                 <div class="player-lobby-actions">
                     <button id="ready-btn">${amReady ? t('not_ready') : t('ready')}</button>
                     ${players.length >= 2 ? '<button id="start-btn" class="btn-success">' + t('start_game') + '</button>' : ''}
@@ -104,6 +140,12 @@
             ws.send('leave', {});
             window.location.href = '/';
         };
+        document.querySelectorAll('[data-add-bot]').forEach(b => {
+            b.onclick = () => ws.send('add_bot', { difficulty: b.dataset.addBot });
+        });
+        document.querySelectorAll('[data-remove-bot]').forEach(b => {
+            b.onclick = () => ws.send('remove_bot', { player_id: b.dataset.removeBot });
+        });
         bindLangSwitcher(render);
     }
 
@@ -126,7 +168,7 @@
         const me = (state.players || []).find(p => p.id === playerID);
         const gold = me ? me.gold : 0;
         const handSize = state.hand ? state.hand.length : 0;
-        const cityScore = me ? (me.city || []).reduce((sum, d) => sum + d.cost, 0) : 0;
+        const cityScore = me ? projectedScore(me) : 0;
 
         let content = `
             <div class="player-header">
@@ -142,6 +184,11 @@
             </div>
         `;
 
+        content += undoHTML();
+        content += autopilotHTML();
+        content += hintHTML();
+        content += premoveHTML();
+
         // Characters
         if (state.characters && state.characters.length > 0) {
             content += `<div class="section">
@@ -216,26 +263,22 @@
                 content += `<div class="ability-section hidden" id="ability-targets">
                     <div class="section-title">${t('choose_target')}</div>`;
                 if (state.current_role === 'Warlord') {
-                    // Group targets by player
+                    // Group district targets by owner
                     const groups = {};
-                    state.valid_targets.forEach(tgt => {
-                        const parts = tgt.split(':');
-                        const pid = parts[0];
-                        if (!groups[pid]) groups[pid] = [];
-                        groups[pid].push(tgt);
+                    (state.ability_targets || []).filter(tgt => tgt.kind === 'district').forEach(tgt => {
+                        if (!groups[tgt.owner]) groups[tgt.owner] = [];
+                        groups[tgt.owner].push(tgt);
                     });
                     for (const pid of Object.keys(groups)) {
                         const player = (state.players || []).find(p => p.id === pid);
                         const playerName = player ? player.name : pid;
+                        const hasGreatWall = player && (player.city || []).some(d => d.name === 'Great Wall');
                         content += `<div class="target-group">
                             <div class="target-group-name">${playerName}</div>
                             <div class="target-list">
                                 ${groups[pid].map(tgt => {
-                                    const districtName = tgt.split(':')[1];
-                                    const district = player && (player.city || []).find(d => d.name === districtName);
-                                    const hasGreatWall = player && (player.city || []).some(d => d.name === 'Great Wall');
-                                    const cost = district ? district.cost - (hasGreatWall ? 0 : 1) : '?';
-                                    return `<div class="target-option" data-target="${tgt}">${t(districtName)} <span class="destroy-cost">(${cost} ${t('gold')})</span></div>`;
+                                    const cost = tgt.card.cost - (hasGreatWall ? 0 : 1);
+                                    return `<div class="target-option" data-target="${pid}:${tgt.card.id}">${t(tgt.card.name)} <span class="destroy-cost">(${cost} ${t('gold')})</span></div>`;
                                 }).join('')}
                             </div>
                         </div>`;
@@ -282,7 +325,7 @@
                     <div class="section-title">${t('lab_select_card')}</div>
                     <div class="hand-cards">
                         ${state.hand.map(d => `
-                            <div class="hand-card lab-discard-card ${colorClass(d.color)}" data-name="${d.name}">
+                            <div class="hand-card lab-discard-card ${colorClass(d.color)}" data-id="${d.id}">
                                 <div><span>${t(d.name)} <small style="color:#888">${colorLabel(d.color)}</small></span>
                                 ${districtEffect(d.name) ? `<div class="card-effect">${districtEffect(d.name)}</div>` : ''}</div>
                                 <span class="cost">${d.cost} ${t('gold')}</span>
@@ -298,7 +341,7 @@
                     <div class="section-title">${t('build_district')}</div>
                     <div class="hand-cards">
                         ${state.hand.map(d => `
-                            <div class="hand-card buildable ${colorClass(d.color)}" data-name="${d.name}">
+                            <div class="hand-card buildable ${colorClass(d.color)}" data-id="${d.id}">
                                 <div><span>${t(d.name)} <small style="color:#888">${colorLabel(d.color)}</small></span>
                                 ${districtEffect(d.name) ? `<div class="card-effect">${districtEffect(d.name)}</div>` : ''}</div>
                                 <span class="cost">${d.cost} ${t('gold')}</span>
@@ -307,6 +350,8 @@
                     </div>
                 </div>`;
             }
+        } else if (state.phase === 'Ability' && !state.decision) {
+            content += `<div class="waiting">${t('waiting_decision')} ${timerBadgeHTML()}</div>`;
         } else if (state.phase === 'PlayerTurn' && !state.is_my_turn) {
             content += `<div class="waiting">${t('waiting')} ${state.current_role ? t(state.current_role) : ''} ${t('waiting_playing')} (${state.current_turn || ''}) ${timerBadgeHTML()}</div>`;
         }
@@ -329,10 +374,11 @@
             }
         }
 
-        // Graveyard prompt (shown regardless of whose turn it is)
-        if (state.graveyard_choice) {
+        // Pending decision (shown regardless of whose turn it is)
+        const decision = state.decision;
+        if (decision && decision.kind === 'graveyard') {
             content += `<div class="section graveyard-prompt" style="background:#2d1b3d;border:2px solid #9b59b6;border-radius:8px;padding:12px;margin:8px 0;">
-                <div style="margin-bottom:8px;">${t('graveyard_prompt', { district: t(state.graveyard_choice.district_name), cost: state.graveyard_choice.district_cost })} ${timerBadgeHTML()}</div>
+                <div style="margin-bottom:8px;">${t('graveyard_prompt', { district: t(decision.card.name), cost: decision.card.cost })} ${timerBadgeHTML()}</div>
                 <div style="display:flex;gap:8px;">
                     <button id="btn-graveyard-accept" style="flex:1;background:#27ae60;">${t('graveyard_accept')}</button>
                     <button id="btn-graveyard-decline" style="flex:1;background:#c0392b;">${t('graveyard_decline')}</button>
@@ -392,6 +438,46 @@
         const btnEnd = document.getElementById('btn-end');
         if (btnEnd) btnEnd.onclick = () => ws.send('end_turn', {});
 
+        // Undo
+        const btnUndo = document.getElementById('btn-undo');
+        if (btnUndo) btnUndo.onclick = () => ws.send('undo_request', {});
+        document.querySelectorAll('[data-undo-vote]').forEach(el => {
+            el.onclick = () => {
+                undoVoted = true;
+                ws.send('undo_vote', { approve: el.dataset.undoVote === 'yes' });
+                render();
+            };
+        });
+
+        // Autopilot
+        const btnAutopilotOn = document.getElementById('btn-autopilot-on');
+        if (btnAutopilotOn) btnAutopilotOn.onclick = () => ws.send('autopilot', { turns: AUTOPILOT_TURNS });
+        const btnAutopilotOff = document.getElementById('btn-autopilot-off');
+        if (btnAutopilotOff) btnAutopilotOff.onclick = () => ws.send('autopilot', { turns: 0 });
+
+        // Pre-moves
+        document.querySelectorAll('[data-premove-role]').forEach(el => {
+            el.onclick = () => {
+                const role = el.dataset.premoveRole;
+                const draft = premoves.draft.includes(role)
+                    ? premoves.draft.filter(r => r !== role)
+                    : [...premoves.draft, role];
+                ws.send('premove', { draft, pass: premoves.pass });
+            };
+        });
+        const btnPremovePass = document.getElementById('btn-premove-pass');
+        if (btnPremovePass) btnPremovePass.onclick = () => ws.send('premove', { draft: premoves.draft, pass: !premoves.pass });
+
+        // Hints
+        const btnHint = document.getElementById('btn-hint');
+        if (btnHint) btnHint.onclick = () => ws.send('hint', {});
+        document.querySelectorAll('[data-hint]').forEach(el => {
+            el.onclick = () => {
+                const { type, ...payload } = hints[el.dataset.hint].action;
+                ws.send(type, payload);
+            };
+        });
+
         const btnAbility = document.getElementById('btn-ability');
         if (btnAbility) {
             btnAbility.onclick = () => {
@@ -424,10 +510,11 @@
                         render();
                     }
                 } else if (role === 'Warlord') {
-                    // target format: "playerID:districtName"
+                    // target format: "playerID:cardID"
                     const parts = target.split(':');
                     if (parts.length === 2) {
-                        ws.send('ability', { target: parts[0], district_name: parts[1] });
+                        // Ask what it costs first; confirmPreview sends the real action
+                        ws.send('preview', { action: 'ability', target: parts[0], card_id: parseInt(parts[1]) });
                     }
                 }
             };
@@ -478,7 +565,7 @@
         // Lab card discard
         document.querySelectorAll('.lab-discard-card').forEach(el => {
             el.onclick = () => {
-                ws.send('lab_discard', { district_name: el.dataset.name });
+                ws.send('lab_discard', { card_id: parseInt(el.dataset.id) });
                 labMode = false;
             };
         });
@@ -508,14 +595,13 @@
         // Build
         document.querySelectorAll('.hand-card.buildable').forEach(el => {
             el.onclick = () => {
-                ws.send('build', { district_name: el.dataset.name });
+                ws.send('build', { card_id: parseInt(el.dataset.id) });
             };
         });
     }
 
     function renderGameOver(app) {
-        const scores = state.scores || [];
-        scores.sort((a, b) => b.total - a.total);
+        const scores = state.scores || []; // ranked by the server, ties broken
         const players = state.players || [];
         app.innerHTML = `
             <div style="padding:16px;">
@@ -533,12 +619,12 @@
                         </tr>
                     </thead>
                     <tbody>
-                        ${scores.map((s, i) => {
+                        ${scores.map(s => {
                             const player = players.find(p => p.id === s.player_id);
                             const city = player ? player.city || [] : [];
                             return `
-                            <tr class="${i === 0 ? 'winner-row' : ''}">
-                                <td>${i === 0 ? '🏆 ' : ''}${s.player_name}</td>
+                            <tr class="${s.winner ? 'winner-row' : ''}">
+                                <td>${s.winner ? '🏆 ' : ''}${s.player_name}</td>
                                 <td>${s.district_score}</td>
                                 <td>${s.color_bonus}</td>
                                 <td>${s.first_complete + s.other_complete}</td>
@@ -554,6 +640,7 @@
                     </tbody>
                 </table>
                 <div style="text-align:center;margin-top:20px;">
+                    <a href="/api/games/${gameID}/summary.png" download="citadels-${gameID}.png"><button>${t('save_summary')}</button></a>
                     <button onclick="location.href='/'">${t('exit_game')}</button>
                 </div>
             </div>
@@ -572,20 +659,20 @@
     }
 
     function translateTarget(target) {
-        // Targets can be character names, "swap_hand", "discard_draw", or "playerID:districtName"
+        // Targets can be character names, "swap_hand", "discard_draw", or "playerID:cardID"
         if (target.includes(':')) {
             const parts = target.split(':');
             const pid = parts[0];
-            const districtName = parts[1];
+            const cardID = parseInt(parts[1]);
             const player = (state.players || []).find(p => p.id === pid);
             const playerName = player ? player.name : pid;
+            const district = player && (player.city || []).find(d => d.id === cardID);
             let costText = '';
-            if (player) {
-                const district = (player.city || []).find(d => d.name === districtName);
+            if (district) {
                 const hasGreatWall = (player.city || []).some(d => d.name === 'Great Wall');
-                if (district) costText = ` (${district.cost - (hasGreatWall ? 0 : 1)} ${t('gold')})`;
+                costText = ` (${district.cost - (hasGreatWall ? 0 : 1)} ${t('gold')})`;
             }
-            return playerName + ': ' + t(districtName) + costText;
+            return playerName + ': ' + (district ? t(district.name) : '?') + costText;
         }
         return t(target);
     }
@@ -595,6 +682,10 @@
         return map[name] || 0;
     }
 
+    function roleNumToName(num) {
+        return ['', 'Assassin', 'Thief', 'Magician', 'King', 'Bishop', 'Merchant', 'Architect', 'Warlord'][num] || '';
+    }
+
     // --- Table panel (other players) ---
 
     function pName(id) {
@@ -638,9 +729,9 @@
                     case 'merchant':
                         return { text: t('ev_merchant_bonus', { player: p }), css: 'ev-ability' };
                     case 'architect':
-                        return { text: t('ev_architect_draw', { player: p, count: d.extra_cards }), css: 'ev-ability' };
+                        return { text: t('ev_architect_draw', { player: p, count: d.extra_cards || 0 }), css: 'ev-ability' };
                     case 'warlord':
-                        return { text: t('ev_warlord_destroy', { player: p, district: t(d.district), target: d.target, cost: d.cost }), css: 'ev-danger' };
+                        return { text: t('ev_warlord_destroy', { player: p, district: t(d.district), target: d.target, cost: d.cost || 0 }), css: 'ev-danger' };
                     case 'laboratory':
                         return { text: t('ev_lab_discard', { player: p, district: t(d.discarded) }), css: 'ev-ability' };
                     case 'smithy':
@@ -668,8 +759,130 @@
         }
     }
 
+    // undoHTML offers to undo my last action, or shows the open request:
+    // mine, waiting for the table, or someone else's, for me to vote on.
+    function undoHTML() {
+        if (undoRequest) {
+            const what = undoActionLabel(undoRequest.action);
+            if (undoRequest.player_id === playerID) {
+                return `<div class="undo-bar">${t('undo_waiting', { action: what })}</div>`;
+            }
+            const text = t('undo_asked', { player: undoRequest.player_name, action: what });
+            if (undoVoted || !(undoRequest.voters || []).includes(playerID)) {
+                return `<div class="undo-bar">${text}</div>`;
+            }
+            return `<div class="undo-bar">
+                <div>${text}</div>
+                <div style="display:flex;gap:8px;margin-top:8px;">
+                    <button data-undo-vote="yes" class="btn-success" style="flex:1;">${t('undo_approve')}</button>
+                    <button data-undo-vote="no" class="btn-danger" style="flex:1;">${t('undo_reject')}</button>
+                </div>
+            </div>`;
+        }
+        if (state.can_undo) {
+            return `<div class="undo-bar"><button id="btn-undo">↶ ${t('undo_btn')}</button></div>`;
+        }
+        return '';
+    }
+
+    // autopilotHTML says the autopilot is playing for me, with a way to take
+    // over, or offers to hand it my next few turns.
+    function autopilotHTML() {
+        if (state.phase === 'GameOver') return '';
+        const mine = autopilot.find(a => a.player_id === playerID);
+        if (mine) {
+            return `<div class="autopilot-bar on">
+                <div>🤖 ${t('autopilot_on_' + mine.reason, { turns: mine.turns })}</div>
+                <button id="btn-autopilot-off" class="btn-success">${t('autopilot_take_over')}</button>
+            </div>`;
+        }
+        return `<div class="autopilot-bar"><button id="btn-autopilot-on">🤖 ${t('autopilot_offer', { turns: AUTOPILOT_TURNS })}</button></div>`;
+    }
+
+    // hintHTML offers the advisor while I have something to decide, or lists
+    // its suggestions, best first, each with a button that plays it.
+    function hintHTML() {
+        if (!lobbyState || !lobbyState.hints || !canAct()) return '';
+        if (!hints) return `<div class="hint-bar"><button id="btn-hint">💡 ${t('hint_btn')}</button></div>`;
+        if (hints.length === 0) return `<div class="hint-bar open">${t('hint_none')}</div>`;
+        return `<div class="hint-bar open">
+            <div class="hint-title">💡 ${t('hint_title')}</div>
+            ${hints.map((h, i) => `<div class="hint">
+                <div><div class="hint-action">${i + 1}. ${hintActionLabel(h.action)}</div><div class="hint-reason">${hintReason(h)}</div></div>
+                <button data-hint="${i}">${t('hint_play')}</button>
+            </div>`).join('')}
+        </div>`;
+    }
+
+    // premoveHTML lets me queue moves while I wait: during the draft, the
+    // characters I want in order of preference, picked for me when my pick
+    // comes; and taking gold and ending my next turn.
+    function premoveHTML() {
+        if (state.phase === 'GameOver' || canAct()) return '';
+        let html = '';
+        if (state.phase === 'DraftPick') {
+            const roles = [1, 2, 3, 4, 5, 6, 7, 8].map(roleNumToName)
+                .filter(r => !(state.draft_face_up || []).includes(r) && !(state.characters || []).includes(r));
+            html += `<div class="premove-title">${t('premove_draft')}</div>
+                <div class="premove-roles">${roles.map(r => {
+                    const rank = premoves.draft.indexOf(r) + 1;
+                    return `<button class="premove-role ${rank ? 'on' : ''}" data-premove-role="${r}" style="--char-color:${characterColor(r)}">${rank ? `<b>${rank}.</b> ` : ''}${t(r)}</button>`;
+                }).join('')}</div>`;
+        }
+        html += `<button id="btn-premove-pass" class="${premoves.pass ? 'on' : ''}">${premoves.pass ? '✓ ' : ''}${t('premove_pass')}</button>`;
+        return `<div class="premove-bar">${html}</div>`;
+    }
+
+    // canAct tells whether the game is waiting for me.
+    function canAct() {
+        return (state.draft_choices && state.draft_choices.length > 0)
+            || (state.drawn_cards && state.drawn_cards.length > 0)
+            || (state.is_my_turn && state.phase === 'PlayerTurn')
+            || !!state.decision;
+    }
+
+    function hintActionLabel(a) {
+        switch (a.type) {
+            case 'draft_pick': return t('hint_act_draft_pick', { character: t(roleNumToName(a.character)) });
+            case 'keep_card': return t('hint_act_keep_card', { district: t((state.drawn_cards[a.index] || {}).name) });
+            case 'build': return t('hint_act_build', { district: t(a.district_name) });
+            case 'lab_discard': return t('hint_act_lab_discard', { district: t(a.district_name) });
+            case 'graveyard_respond': return t('hint_act_graveyard_' + a.extra_data);
+            case 'ability':
+                if (a.extra_data === 'swap_hand') return t('hint_act_swap', { player: pName(a.target) });
+                if (a.extra_data === 'discard_draw') return t('hint_act_discard', { cards: (a.indices || []).length });
+                if (a.district_name) return t('hint_act_destroy', { district: t(a.district_name), player: pName(a.target) });
+                return t('hint_act_target', { character: t(roleNumToName(a.character)) });
+        }
+        return t('hint_act_' + a.type);
+    }
+
+    function hintReason(h) {
+        const p = Object.assign({}, h.params);
+        if (p.district) p.district = t(p.district);
+        if (p.character) p.character = t(p.character);
+        if (p.color) p.color = colorLabel(p.color);
+        return t('hint_' + h.reason, p);
+    }
+
+    function undoActionLabel(a) {
+        if (a.type === 'build') return t('undo_act_build', { district: t(a.district_name) });
+        return t('undo_act_' + a.type);
+    }
+
+    function undoResultEntry(r) {
+        const player = pName(r.player_id);
+        const what = undoActionLabel(r.action);
+        if (r.undone) return { text: t('ev_undone', { player: player, action: what }), css: 'ev-round' };
+        if (r.reason === 'rejected') return { text: t('ev_undo_rejected', { player: player, by: r.by }), css: 'ev-minor' };
+        return { text: t('ev_undo_cancelled', { player: player }), css: 'ev-minor' };
+    }
+
     function pushEvent(ev) {
-        const entry = formatEvent(ev);
+        pushEntry(formatEvent(ev));
+    }
+
+    function pushEntry(entry) {
         if (entry) {
             eventLog.push(entry);
             if (eventLog.length > MAX_LOG) eventLog.shift();
@@ -690,10 +903,14 @@
                 <div class="hand-cards">
                     ${others.map(p => {
                         const isActive = state.current_turn === p.name;
-                        const score = (p.city || []).reduce((sum, d) => sum + d.cost, 0);
+                        const score = projectedScore(p);
                         const roles = (p.revealed_roles && p.revealed_roles.length > 0)
                             ? `<div style="margin-top:4px;font-size:13px;">${p.revealed_roles.map(r => `<span style="color:${characterColor(r)}">${t(r)}</span>`).join(', ')}</div>`
                             : '';
+                        const possible = (state.possible_characters || {})[p.id];
+                        const couldBe = possible
+                            ? `<div style="margin-top:4px;font-size:12px;opacity:0.7;">${t('could_be', { roles: possible.map(r => t(r)).join(', ') })}</div>`
+                            : '';
                         const city = (p.city || []).map(d =>
                             `<span class="district-chip ${colorClass(d.color)}">${t(d.name)} (${d.cost})${districtEffect(d.name) ? `<span class="district-effect">${districtEffect(d.name)}</span>` : ''}</span>`
                         ).join(' ');
@@ -703,6 +920,7 @@
                                 <span style="font-size:13px;"><span class="stat-gold">${p.gold} ${t('gold')}</span> · <span class="stat-cards">${p.hand_size} ${t('cards')}</span> · <span class="stat-pts">${score} ${t('pts')}</span></span>
                             </div>
                             ${roles}
+                            ${couldBe}
                             ${city ? `<div style="margin-top:4px;">${city}</div>` : ''}
                         </div>`;
                     }).join('')}
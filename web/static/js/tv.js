@@ -1,7 +1,8 @@
 // TV screen logic
 (function() {
     const params = new URLSearchParams(location.search);
-    const gameID = params.get('game');
+    const replayID = params.get('replay'); // replay of a finished game, see startReplay
+    const gameID = params.get('game') || replayID;
     if (!gameID) {
         document.body.innerHTML = '<div class="container"><h1>' + t('no_game_id') + '</h1><p><a href="/api/create">Create a new game</a></p></div>';
         return;
@@ -11,15 +12,20 @@
     let state = null;
     let lobbyCopied = false;
     const logKey = 'citadels_log_tv_' + gameID;
-    const eventLog = JSON.parse(sessionStorage.getItem(logKey) || '[]');
+    const eventLog = replayID ? [] : JSON.parse(sessionStorage.getItem(logKey) || '[]');
     const MAX_LOG = 50;
     let timerInterval = null;
+    let undoRequest = null; // open undo_requested; players vote on their phones
+    let autopilot = []; // autopilot_update players, shown on their cards
 
-    const ws = new WS(wsUrl,
+    const ws = replayID ? null : new WS(wsUrl,
         (env) => {
             if (env.type === 'lobby_update') renderLobby(env.payload);
             else if (env.type === 'game_state') { state = env.payload; renderGame(); }
             else if (env.type === 'event') handleEvent(env.payload);
+            else if (env.type === 'undo_requested') { undoRequest = env.payload; renderGame(); }
+            else if (env.type === 'undo_result') { undoRequest = null; logEntry(undoResultEntry(env.payload)); }
+            else if (env.type === 'autopilot_update') { autopilot = env.payload.players || []; if (state) renderGame(); }
         },
         () => console.log('TV connected'),
         () => console.log('TV disconnected')
@@ -36,7 +42,7 @@
         const joinUrl = 'http://' + location.host + '/lobby.html?game=' + gameID;
         const players = data.players || [];
         const playersHTML = players.length > 0
-            ? players.map(p => `<div class="lobby-player ${p.ready ? 'ready' : ''}">${p.name} ${p.ready ? '✓' : '...'}</div>`).join('')
+            ? players.map(lobbyPlayerHTML).join('')
             : `<div class="lobby-empty">${t('waiting_for_players')}</div>`;
         app.innerHTML = `
             <div class="lobby-screen">
@@ -59,7 +65,9 @@
                     <div class="lobby-players-area">
                         <p class="lobby-players-label">${players.length} ${t('players_joined')}</p>
                         <div class="lobby-players">${playersHTML}</div>
+                        ${players.length < 7 ? addBotHTML() : ''}
                     </div>
+                    ${settingsHTML(data)}
                 </div>
                 <div class="lobby-footer">
                     <button onclick="location.href='/'" class="lobby-back-btn">${t('leave_lobby')}</button>
@@ -68,6 +76,8 @@
             </div>
         `;
         bindLangSwitcher(rerender);
+        bindBotButtons();
+        bindSettings(data);
         const copyBtn = document.getElementById('copy-link-btn');
         if (copyBtn) copyBtn.onclick = () => copyLink(joinUrl);
         if (!lobbyCopied) {
@@ -88,6 +98,43 @@
         }
     }
 
+    function lobbyPlayerHTML(p) {
+        if (p.bot) {
+            return `<div class="lobby-player ready bot">🤖 ${p.name} <span class="bot-level">${t('bot_' + p.bot)}</span>
+                <button class="bot-remove" data-remove-bot="${p.id}" title="${t('remove_bot')}">✕</button></div>`;
+        }
+        return `<div class="lobby-player ${p.ready ? 'ready' : ''}">${p.name} ${p.ready ? '✓' : '...'}</div>`;
+    }
+
+    function addBotHTML() {
+        return `<div class="lobby-bots">${['easy', 'normal', 'hard'].map(d =>
+            `<button class="lobby-bot-btn" data-add-bot="${d}">${t('add_bot_' + d)}</button>`).join('')}</div>`;
+    }
+
+    function bindBotButtons() {
+        document.querySelectorAll('[data-add-bot]').forEach(b => {
+            b.onclick = () => ws.send('add_bot', { difficulty: b.dataset.addBot });
+        });
+        document.querySelectorAll('[data-remove-bot]').forEach(b => {
+            b.onclick = () => ws.send('remove_bot', { player_id: b.dataset.removeBot });
+        });
+    }
+
+    // settingsHTML shows the lobby settings; a rated game can't have hints.
+    function settingsHTML(data) {
+        return `<div class="lobby-settings">
+            <label><input type="checkbox" id="setting-hints" ${data.hints ? 'checked' : ''} ${data.rated ? 'disabled' : ''}> ${t('setting_hints')}</label>
+            <label><input type="checkbox" id="setting-rated" ${data.rated ? 'checked' : ''}> ${t('setting_rated')}</label>
+        </div>`;
+    }
+
+    function bindSettings(data) {
+        const hints = document.getElementById('setting-hints');
+        const rated = document.getElementById('setting-rated');
+        if (hints) hints.onchange = () => ws.send('settings', { hints: hints.checked, rated: data.rated });
+        if (rated) rated.onchange = () => ws.send('settings', { hints: data.hints, rated: rated.checked });
+    }
+
     function copyToClipboard(url) {
         function fallbackCopy() {
             const ta = document.createElement('textarea');
@@ -163,6 +210,8 @@
                     ${langSwitcherHTML()}
                 </div>
             </div>
+            ${replayBarHTML()}
+            ${undoBannerHTML()}
             ${draftHTML}
             <div class="tv-turn-section">
                 ${characterBarHTML(state)}
@@ -185,51 +234,66 @@
         const logList = document.getElementById('event-log-list');
         if (logList) logList.scrollTop = logList.scrollHeight;
         bindLangSwitcher(rerender);
+        bindReplayBar();
         startTimerCountdown();
     }
 
-    function cityScore(p) {
-        return (p.city || []).reduce((sum, d) => sum + d.cost, 0);
+    // Running score from the server's projection (district points, color
+    // and completion bonuses, public purple bonuses)
+    function projection(p) {
+        return (state.projection || []).find(s => s.player_id === p.id)
+            || { total: (p.city || []).reduce((sum, d) => sum + d.cost, 0), colors: 0 };
+    }
+
+    // autopilotBadgeHTML marks a player the autopilot is playing for.
+    function autopilotBadgeHTML(p) {
+        const a = autopilot.find(a => a.player_id === p.id);
+        if (!a) return '';
+        return ` <span class="autopilot-badge" title="${t('autopilot_reason_' + a.reason)}">🤖 ${t('autopilot')}</span>`;
     }
 
     function renderPlayerCard(p) {
         const isActive = state.current_turn === p.name;
+        const proj = projection(p);
+        const roles = p.characters || p.revealed_roles || []; // characters: replay with hands revealed
         return `
             <div class="player-card ${isActive ? 'active' : ''}">
-                <div class="name ${p.has_crown ? 'crown' : ''}">${p.name}</div>
+                <div class="name ${p.has_crown ? 'crown' : ''}">${p.name}${autopilotBadgeHTML(p)}</div>
                 <div class="stats">
                     <span class="stat-gold">${p.gold} ${t('gold')}</span>
                     <span class="stat-cards">${p.hand_size} ${t('cards')}</span>
-                    <span class="stat-pts">${cityScore(p)} ${t('pts')}</span>
+                    <span class="stat-pts">${proj.total} ${t('pts')}</span>
+                    <span class="stat-colors">${t('colors')} ${proj.colors}/5</span>
                 </div>
-                ${p.revealed_roles && p.revealed_roles.length > 0 ?
-                    `<div style="margin:4px 0;">${p.revealed_roles.map(r => `<span style="color:${characterColor(r)}">${t(r)}</span>`).join(', ')}</div>` : ''}
+                ${roles.length > 0 ?
+                    `<div style="margin:4px 0;">${roles.map(r => `<span style="color:${characterColor(r)}">${t(r)}</span>`).join(', ')}</div>` : ''}
                 <div class="city-districts">
                     ${(p.city || []).map(d => `<span class="district-chip ${colorClass(d.color)}">${t(d.name)} (${d.cost})${districtEffect(d.name) ? `<span class="district-effect">${districtEffect(d.name)}</span>` : ''}</span>`).join('')}
                 </div>
+                ${p.hand ? `<div class="replay-hand">${p.hand.map(d => `<span class="district-chip ${colorClass(d.color)}">${t(d.name)} (${d.cost})</span>`).join('')}</div>` : ''}
             </div>
         `;
     }
 
     function renderGameOver(app) {
-        const scores = state.scores || [];
-        scores.sort((a, b) => b.total - a.total);
+        const scores = state.scores || []; // ranked by the server, ties broken
         const players = state.players || [];
         app.innerHTML = `
             <div class="tv-header">
                 <h1>${t('game_over')}</h1>
                 ${langSwitcherHTML()}
             </div>
+            ${replayBarHTML()}
             <table class="scores-table">
                 <thead>
                     <tr><th>${t('player')}</th><th>${t('districts')}</th><th>${t('colors')}</th><th>${t('complete')}</th><th>${t('special')}</th><th>${t('total')}</th></tr>
                 </thead>
                 <tbody>
-                    ${scores.map((s, i) => {
+                    ${scores.map(s => {
                         const player = players.find(p => p.id === s.player_id);
                         const city = player ? player.city || [] : [];
                         return `
-                        <tr class="${i === 0 ? 'winner-row' : ''}">
+                        <tr class="${s.winner ? 'winner-row' : ''}">
                             <td>${s.player_name}</td>
                             <td>${s.district_score}</td>
                             <td>${s.color_bonus}</td>
@@ -245,15 +309,40 @@
                     }).join('')}
                 </tbody>
             </table>
+            ${renderStats()}
             <div style="text-align:center;margin-top:20px;">
+                <a href="/api/games/${gameID}/summary.png" download="citadels-${gameID}.png"><button>${t('save_summary')}</button></a>
+                <a href="/api/games/${gameID}/record"><button>${t('export_record')}</button></a>
+                ${replayID ? '' : `<a href="/tv.html?replay=${gameID}"><button>${t('watch_replay')}</button></a>`}
                 <button onclick="location.href='/'">${t('exit_game')}</button>
             </div>
         `;
         bindLangSwitcher(rerender);
+        bindReplayBar();
+    }
+
+    // Per-player game statistics (server-side Stats aggregator)
+    function renderStats() {
+        const stats = state.stats;
+        if (!stats || !stats.players) return '';
+        const cols = ['gold_earned', 'gold_stolen', 'gold_lost', 'cards_drawn', 'built', 'destroyed', 'districts_lost', 'murdered', 'robbed', 'turns'];
+        return `
+            <h2 style="text-align:center;margin-top:20px;">${t('stats_title')}</h2>
+            <table class="scores-table">
+                <thead>
+                    <tr><th>${t('player')}</th>${cols.map(c => `<th>${t('stat_' + c)}</th>`).join('')}</tr>
+                </thead>
+                <tbody>
+                    ${stats.players.map(p => `<tr><td>${p.player_name}</td>${cols.map(c => `<td>${p[c]}</td>`).join('')}</tr>`).join('')}
+                </tbody>
+            </table>`;
     }
 
     function handleEvent(ev) {
-        const entry = formatEvent(ev);
+        logEntry(formatEvent(ev));
+    }
+
+    function logEntry(entry) {
         if (entry) {
             eventLog.push(entry);
             if (eventLog.length > MAX_LOG) eventLog.shift();
@@ -262,6 +351,31 @@
         }
     }
 
+    // An open undo request. Players vote on their phones; the TV only shows
+    // it, since anyone can open a TV page.
+    function undoBannerHTML() {
+        if (!undoRequest) return '';
+        return `
+            <div class="undo-banner">
+                <span>${t('undo_asked', { player: undoRequest.player_name, action: undoActionLabel(undoRequest.action) })}</span>
+                <span>${t('undo_vote_on_phones')}</span>
+            </div>
+        `;
+    }
+
+    function undoActionLabel(a) {
+        if (a.type === 'build') return t('undo_act_build', { district: t(a.district_name) });
+        return t('undo_act_' + a.type);
+    }
+
+    function undoResultEntry(r) {
+        const player = pName(r.player_id);
+        const what = undoActionLabel(r.action);
+        if (r.undone) return { text: t('ev_undone', { player: player, action: what }), css: 'ev-round' };
+        if (r.reason === 'rejected') return { text: t('ev_undo_rejected', { player: player, by: r.by }), css: 'ev-minor' };
+        return { text: t('ev_undo_cancelled', { player: player }), css: 'ev-minor' };
+    }
+
     function pName(id) {
         if (!state || !state.players) return id;
         const p = state.players.find(p => p.id === id);
@@ -303,9 +417,9 @@
                     case 'merchant':
                         return { text: t('ev_merchant_bonus', { player: p }), css: 'ev-ability' };
                     case 'architect':
-                        return { text: t('ev_architect_draw', { player: p, count: d.extra_cards }), css: 'ev-ability' };
+                        return { text: t('ev_architect_draw', { player: p, count: d.extra_cards || 0 }), css: 'ev-ability' };
                     case 'warlord':
-                        return { text: t('ev_warlord_destroy', { player: p, district: t(d.district), target: d.target, cost: d.cost }), css: 'ev-danger' };
+                        return { text: t('ev_warlord_destroy', { player: p, district: t(d.district), target: d.target, cost: d.cost || 0 }), css: 'ev-danger' };
                     case 'laboratory':
                         return { text: t('ev_lab_discard', { player: p, district: t(d.discarded) }), css: 'ev-ability' };
                     case 'smithy':
@@ -355,6 +469,129 @@
         }, 1000);
     }
 
-    // Initial lobby render
-    renderLobby({ players: [], started: false });
+    // Replay of a finished game (tv.html?replay={id}). The server rebuilds
+    // the game at any step from its record; the TV just asks for frames.
+    const replay = { steps: 0, step: 0, marks: [], playing: false, speed: 1, reveal: false, timer: null };
+    const REPLAY_DELAY = 1500; // ms per step at speed 1
+
+    function startReplay() {
+        fetch(`/api/games/${replayID}/replay`)
+            .then(r => r.ok ? r.json() : r.text().then(msg => Promise.reject(msg)))
+            .then(index => {
+                replay.steps = index.steps;
+                replay.marks = index.marks || [];
+                seekReplay(0);
+            })
+            .catch(err => {
+                document.getElementById('tv-app').innerHTML = `<div class="container"><h1>${t('replay_unavailable')}</h1><p>${err}</p></div>`;
+            });
+    }
+
+    // seekReplay shows the game after step moves. log is 'append' when
+    // stepping forward, 'keep' to redraw the same step, otherwise the event
+    // log starts over from the frame's events.
+    function seekReplay(step, log) {
+        step = Math.max(0, Math.min(replay.steps, step));
+        return fetch(`/api/games/${replayID}/replay/${step}${replay.reveal ? '?reveal=1' : ''}`)
+            .then(r => r.json())
+            .then(frame => {
+                replay.step = frame.step;
+                state = frame.view;
+                if (log !== 'keep') {
+                    if (log !== 'append') eventLog.length = 0;
+                    (frame.events || []).forEach(ev => {
+                        const entry = formatEvent(ev);
+                        if (entry) eventLog.push(entry);
+                    });
+                }
+                while (eventLog.length > MAX_LOG) eventLog.shift();
+                renderGame();
+            });
+    }
+
+    function playReplay() {
+        clearTimeout(replay.timer);
+        if (!replay.playing) return;
+        if (replay.step >= replay.steps) {
+            replay.playing = false;
+            renderGame();
+            return;
+        }
+        replay.timer = setTimeout(() => seekReplay(replay.step + 1, 'append').then(playReplay), REPLAY_DELAY / replay.speed);
+    }
+
+    function replayControl(cmd) {
+        const rounds = replay.marks.filter(m => m.kind === 'round');
+        switch (cmd) {
+            case 'play':
+                replay.playing = !replay.playing;
+                renderGame();
+                playReplay();
+                break;
+            case 'back':
+                seekReplay(replay.step - 1);
+                break;
+            case 'forward':
+                seekReplay(replay.step + 1, 'append');
+                break;
+            case 'prev-round': {
+                const m = rounds.filter(m => m.step < replay.step).pop();
+                seekReplay(m ? m.step : 0);
+                break;
+            }
+            case 'next-round': {
+                const m = rounds.find(m => m.step > replay.step);
+                seekReplay(m ? m.step : replay.steps);
+                break;
+            }
+        }
+    }
+
+    function replayBarHTML() {
+        if (!replayID) return '';
+        const current = replay.marks.filter(m => m.step <= replay.step).pop();
+        const markLabel = m => m.kind === 'round'
+            ? `${t('round')} ${m.round}`
+            : `${t('round')} ${m.round}: ${t(m.role)} (${m.player})`;
+        return `
+            <div class="replay-bar">
+                <span class="replay-title">${t('replay')}</span>
+                <button data-replay="prev-round" title="${t('replay_prev_round')}">⏮</button>
+                <button data-replay="back" title="${t('replay_back')}">⏪</button>
+                <button data-replay="play">${replay.playing ? '⏸' : '▶'}</button>
+                <button data-replay="forward" title="${t('replay_forward')}">⏩</button>
+                <button data-replay="next-round" title="${t('replay_next_round')}">⏭</button>
+                <input type="range" id="replay-seek" min="0" max="${replay.steps}" value="${replay.step}">
+                <span class="replay-step">${replay.step} / ${replay.steps}</span>
+                <select id="replay-mark">
+                    ${replay.marks.map(m => `<option value="${m.step}" ${m === current ? 'selected' : ''}>${markLabel(m)}</option>`).join('')}
+                </select>
+                <select id="replay-speed">
+                    ${[0.5, 1, 2, 4].map(v => `<option value="${v}" ${v === replay.speed ? 'selected' : ''}>${v}×</option>`).join('')}
+                </select>
+                <label><input type="checkbox" id="replay-reveal" ${replay.reveal ? 'checked' : ''}> ${t('replay_reveal')}</label>
+            </div>
+        `;
+    }
+
+    function bindReplayBar() {
+        if (!replayID) return;
+        document.querySelectorAll('[data-replay]').forEach(b => {
+            b.onclick = () => replayControl(b.dataset.replay);
+        });
+        const seek = document.getElementById('replay-seek');
+        seek.onchange = () => seekReplay(parseInt(seek.value));
+        const mark = document.getElementById('replay-mark');
+        mark.onchange = () => seekReplay(parseInt(mark.value));
+        const speed = document.getElementById('replay-speed');
+        speed.onchange = () => { replay.speed = parseFloat(speed.value); playReplay(); };
+        const reveal = document.getElementById('replay-reveal');
+        reveal.onchange = () => {
+            replay.reveal = reveal.checked;
+            seekReplay(replay.step, 'keep');
+        };
+    }
+
+    if (replayID) startReplay();
+    else renderLobby({ players: [], started: false });
 })();
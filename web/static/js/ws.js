@@ -1,4 +1,7 @@
 // Shared WebSocket manager with reconnect
+const PROTOCOL_VERSION = 2;
+const PROTOCOL_FEATURES = ['events', 'undo', 'autopilot', 'premoves'];
+
 class WS {
     constructor(url, onMessage, onOpen, onClose) {
         this.url = url;
@@ -40,11 +43,16 @@ class WS {
         this.ws = new WebSocket(this.url);
         this.ws.onopen = () => {
             this.reconnectDelay = 1000;
+            this.send('hello', { version: PROTOCOL_VERSION, features: PROTOCOL_FEATURES });
             this.onOpen();
         };
         this.ws.onmessage = (e) => {
             try {
                 const env = JSON.parse(e.data);
+                if (env.type === 'welcome') {
+                    this.protocol = env.payload;
+                    return;
+                }
                 this.onMessage(env);
             } catch (err) {
                 console.error('WS parse error:', err);